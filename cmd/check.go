@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"eth-rewards-api/internal/config"
+	"eth-rewards-api/internal/services"
+)
+
+// checkRequested reports whether the service should only check its configuration, as requested by the --check flag
+// or a true CHECK_CONFIG environment variable.
+func checkRequested(flagSet bool) bool {
+	if flagSet {
+		return true
+	}
+	enabled, err := strconv.ParseBool(os.Getenv("CHECK_CONFIG"))
+	return err == nil && enabled
+}
+
+// runCheck prints the resolved configuration with credentials masked, verifies that the beacon node answers a head
+// slot request and that the execution client serves the configured network. It returns the exit code: 0 when the
+// checks passed and 1 otherwise.
+func runCheck(cfg *config.Config, network services.NetworkConfig, consensusService *services.ConsensusService, executionService *services.ExecutionService) int {
+	fmt.Println("Resolved configuration:")
+	printFields(redactConfig(*cfg))
+	fmt.Println("Network:")
+	printFields(network)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConsensusTimeout)
+	defer cancel()
+	headSlot, err := consensusService.GetHeadSlot(ctx)
+	if err != nil {
+		fmt.Printf("Consensus endpoint check failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Consensus endpoint reachable, head slot %d\n", headSlot)
+
+	chainID, err := checkChainID(network, executionService, cfg.ExecutionTimeout)
+	if err != nil {
+		fmt.Printf("Execution endpoint check failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Execution endpoint reachable, chain ID %d\n", chainID)
+	return 0
+}
+
+// errChainIDMismatch is returned by checkChainID when the execution client serves another network than the configured one.
+var errChainIDMismatch = errors.New("chain ID mismatch")
+
+// checkChainID verifies that the execution client serves the configured network by comparing its chain ID with the
+// network's. It returns the chain ID the client reported, and an error if it could not be fetched or does not match.
+// Networks without a known chain ID are not checked.
+func checkChainID(network services.NetworkConfig, executionService *services.ExecutionService, timeout time.Duration) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	chainID, err := executionService.GetChainID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch chain ID: %w", err)
+	}
+	if network.ChainID != 0 && chainID != network.ChainID {
+		return chainID, fmt.Errorf("%w: execution endpoint serves chain ID %d, but network %s has chain ID %d", errChainIDMismatch, chainID, network.Name, network.ChainID)
+	}
+	return chainID, nil
+}
+
+// redactConfig masks the API keys, the upstream override token and the credentials embedded in the upstream URLs of a configuration.
+func redactConfig(cfg config.Config) config.Config {
+	cfg.ConsensusEndpoint = services.SanitizeURL(cfg.ConsensusEndpoint)
+	cfg.ExecutionEndpoint = services.SanitizeURL(cfg.ExecutionEndpoint)
+	relayURLs := make([]string, len(cfg.RelayURLs))
+	for i, relayURL := range cfg.RelayURLs {
+		relayURLs[i] = services.SanitizeURL(relayURL)
+	}
+	cfg.RelayURLs = relayURLs
+	overrideURLs := make(map[string]string, len(cfg.UpstreamOverrideURLs))
+	for alias, overrideURL := range cfg.UpstreamOverrideURLs {
+		overrideURLs[alias] = services.SanitizeURL(overrideURL)
+	}
+	cfg.UpstreamOverrideURLs = overrideURLs
+	if cfg.UpstreamOverrideToken != "" {
+		cfg.UpstreamOverrideToken = "REDACTED"
+	}
+	apiKeys := make([]string, len(cfg.APIKeys))
+	for i := range apiKeys {
+		apiKeys[i] = "REDACTED"
+	}
+	cfg.APIKeys = apiKeys
+	return cfg
+}
+
+// printFields prints every field of a struct on its own line.
+func printFields(v any) {
+	value := reflect.ValueOf(v)
+	for i := 0; i < value.NumField(); i++ {
+		fmt.Printf("  %s: %v\n", value.Type().Field(i).Name, value.Field(i).Interface())
+	}
+}
@@ -4,48 +4,221 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"eth-rewards-api/internal/config"
 	"eth-rewards-api/internal/handlers"
+	"eth-rewards-api/internal/logging"
+	"eth-rewards-api/internal/metrics"
+	"eth-rewards-api/internal/middleware"
 	"eth-rewards-api/internal/services"
+	"eth-rewards-api/internal/version"
+	"flag"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv" // For loading .env file
+	"golang.org/x/sync/semaphore"
 )
 
 func main() {
+	// With --check, validate the configuration and the beacon node's reachability, then exit without serving.
+	check := flag.Bool("check", false, "validate the configuration and upstream connectivity, then exit")
+	flag.Parse()
+
 	// Attempt to load environment variables from a .env file.
 	// If the file is not found or fails to load, log a message but continue execution.
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found or failed to load.")
 	}
 
-	// Retrieve the QUICKNODE_ENDPOINT environment variable, which is expected to contain the endpoint URL.
-	// If the variable is not set, log a fatal error and terminate the program.
-	endpoint := os.Getenv("QUICKNODE_ENDPOINT")
-	if endpoint == "" {
-		log.Fatal("QUICKNODE_ENDPOINT environment variable not set.")
+	// Load and validate the configuration from the environment.
+	// If any setting is missing or invalid, log a fatal error and terminate the program.
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	// Initialize services for consensus and execution layers using the endpoint.
-	consensusService := services.NewConsensusService(endpoint)
-	executionService := services.NewExecutionService(endpoint)
+	// Write structured JSON logs from here on; output of the standard log package is routed through the same logger.
+	slog.SetDefault(logging.New(os.Stdout, cfg.LogLevel))
 
-	// Create a new Gin router instance.
-	r := gin.Default()
+	// Retry transient upstream failures with exponential backoff.
+	retryPolicy := services.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = cfg.RetryMaxAttempts
+	retryPolicy.BaseDelay = cfg.RetryBaseDelay
 
-	// Create a new BlockRewardHandler with the initialized services.
-	blockRewardHandler := handlers.NewBlockRewardHandler(consensusService, executionService)
+	log.Printf("Starting build %s (built %s, %s)", version.Commit, version.BuildTime, runtime.Version())
 
-	// Define an HTTP GET endpoint for retrieving block rewards by slot.
-	r.GET("/blockreward/:slot", blockRewardHandler.GetBlockReward)
+	// Share one pooled transport between the services so concurrent upstream requests reuse their connections.
+	transport := services.NewTransport(services.TransportConfig{
+		MaxIdleConns:        cfg.HTTPMaxIdleConns,
+		MaxIdleConnsPerHost: cfg.HTTPMaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.HTTPMaxConnsPerHost,
+		IdleConnTimeout:     cfg.HTTPIdleConnTimeout,
+	})
 
-	// Define an HTTP GET endpoint for retrieving sync committee duties by slot.
-	r.GET("/syncduties/:slot", blockRewardHandler.GetSyncDuties)
+	// Options shared by both services. One semaphore bounds their in-flight requests together, since they often share a provider quota.
+	upstreamOpts := []services.Option{services.WithRetryPolicy(retryPolicy), services.WithTransport(transport), services.WithUpstreamDebug(cfg.DebugUpstream), services.WithUserAgent(cfg.UserAgent)}
+	if cfg.MaxUpstreamConcurrency > 0 {
+		upstreamOpts = append(upstreamOpts, services.WithConcurrencyLimit(semaphore.NewWeighted(int64(cfg.MaxUpstreamConcurrency))))
+	}
 
-	// Start the Gin server on port 8080.
-	// If the server fails to start, log a fatal error and terminate the program.
-	if err := r.Run(":8080"); err != nil {
+	// Resolve the chain parameters of the configured network, discovering its constants from the beacon node's spec
+	// and applying any explicit overrides on top. If the network is unknown, log a fatal error rather than serve data
+	// computed with the wrong parameters.
+	network, err := services.LookupNetwork(cfg.Network)
+	if err != nil {
 		log.Fatal(err)
 	}
+	specService := services.NewConsensusService(cfg.ConsensusEndpoint, append([]services.Option{services.WithTimeout(cfg.ConsensusTimeout)}, upstreamOpts...)...)
+	network = discoverNetwork(network, specService, cfg.ConsensusTimeout)
+	if cfg.GenesisTime != 0 {
+		network.GenesisTime = cfg.GenesisTime
+	}
+	if cfg.SecondsPerSlot != 0 {
+		network.SecondsPerSlot = uint64(cfg.SecondsPerSlot)
+	}
+	if cfg.MergeSlot >= 0 {
+		network.MergeSlot = uint64(cfg.MergeSlot)
+	}
+	if cfg.HeadSlotTTL >= time.Duration(network.SecondsPerSlot)*time.Second {
+		log.Fatalf("HEAD_SLOT_TTL must be shorter than a slot (%ds)", network.SecondsPerSlot)
+	}
+	log.Printf("Serving network %s", network.Name)
+
+	// Initialize services for consensus and execution layers using their respective endpoints.
+	consensusService := services.NewConsensusService(cfg.ConsensusEndpoint, append([]services.Option{services.WithNetwork(network), services.WithTimeout(cfg.ConsensusTimeout), services.WithHeadSlotTTL(cfg.HeadSlotTTL)}, upstreamOpts...)...)
+	executionService := services.NewExecutionService(cfg.ExecutionEndpoint, append([]services.Option{services.WithTimeout(cfg.ExecutionTimeout), services.WithTransactionHashesOnly(cfg.ExecutionTxHashesOnly)}, upstreamOpts...)...)
+	if checkRequested(*check) {
+		os.Exit(runCheck(cfg, network, consensusService, executionService))
+	}
+	log.Printf("Upstream timeouts: consensus %s, execution %s", cfg.ConsensusTimeout, cfg.ExecutionTimeout)
+
+	// Verify that the execution client serves the configured network, since a mismatch would silently serve wrong data.
+	// A mismatch is fatal with STRICT_NETWORK_CHECK; an unreachable client never is, as it may come up after the service.
+	if chainID, err := checkChainID(network, executionService, cfg.ExecutionTimeout); err != nil {
+		if cfg.StrictNetworkCheck && errors.Is(err, errChainIDMismatch) {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: %v", err)
+	} else {
+		log.Printf("Execution endpoint serves chain ID %d", chainID)
+	}
+
+	// Enrich block rewards with the data of MEV-Boost relays when any are configured.
+	var relayOpts []handlers.HandlerOption
+	if len(cfg.RelayURLs) > 0 {
+		relayOpts = append(relayOpts, handlers.WithRelays(services.NewRelayService(cfg.RelayURLs, upstreamOpts...)))
+		log.Printf("Reading delivered payloads from %d MEV-Boost relays", len(cfg.RelayURLs))
+	}
+	if cfg.MaxUpstreamConcurrency > 0 {
+		log.Printf("Upstream concurrency limited to %d requests", cfg.MaxUpstreamConcurrency)
+	}
+	if cfg.DebugUpstream {
+		log.Println("DEBUG_UPSTREAM enabled, logging every upstream request and response.")
+	}
+
+	// Stop on SIGINT or SIGTERM; background work such as the head event subscription ends with ctx.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Follow new heads through the beacon node's event stream, which keeps the head slot current without polling.
+	// Nodes that do not offer the stream fall back to fetching the head slot when needed.
+	handlerOpts := append([]handlers.HandlerOption{handlers.WithRewardCache(cfg.RewardCacheSize), handlers.WithAllowedOrigins(cfg.CORSOrigins), handlers.WithExecutionLagRetry(cfg.ExecutionLagRetryAttempts, cfg.ExecutionLagRetryDelay)}, relayOpts...)
+	if heads, err := consensusService.SubscribeHeads(ctx); err != nil {
+		log.Printf("Head event stream unavailable, polling the head slot instead: %v", err)
+	} else {
+		handlerOpts = append(handlerOpts, handlers.WithHeadEvents(heads))
+	}
+
+	// Create a new Gin router instance that tags every request with an ID, logs it as JSON and turns panics into JSON 500 errors.
+	// Recovery runs inside the logger so that requests which panicked are still logged with their 500 status.
+	r := gin.New()
+	r.Use(middleware.RequestID(), middleware.Logger(), middleware.Recovery())
+
+	// Allow browsers on the configured origins to call the API; preflight requests are answered before authentication.
+	r.Use(middleware.CORS(cfg.CORSOrigins))
+
+	// Compress large responses unless compression is left to a proxy in front of the service.
+	if cfg.CompressionEnabled {
+		r.Use(middleware.Gzip(cfg.CompressionMinSize))
+	}
+
+	// Require one of the configured API keys on every route except the probes, the metrics scrape and the API documentation.
+	// When no keys are configured, the API stays open.
+	r.Use(middleware.APIKeyAuth(cfg.APIKeys, "/healthz", "/readyz", "/metrics", "/openapi.json", "/docs"))
+	if len(cfg.APIKeys) == 0 {
+		log.Println("API_KEYS not set, API key authentication is disabled.")
+	}
+
+	// Shed excess load per client before it reaches the upstream providers and their rate limits.
+	r.Use(middleware.RateLimit(cfg.RateLimitRPS, cfg.RateLimitBurst, len(cfg.APIKeys) > 0, "/healthz", "/readyz", "/metrics"))
+
+	// Record request latency for every route and expose the collected metrics for Prometheus.
+	metrics.Register()
+	r.Use(middleware.Metrics())
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Create a new HealthHandler and define the liveness and readiness probe endpoints.
+	healthHandler := handlers.NewHealthHandler(consensusService, executionService)
+	r.GET("/healthz", healthHandler.Healthz)
+	r.GET("/readyz", healthHandler.Readyz)
+
+	// Report which build is running.
+	r.GET("/version", handlers.GetVersion)
+
+	// Serve the OpenAPI specification and the Swagger UI rendering it.
+	r.GET("/openapi.json", handlers.GetOpenAPISpec)
+	r.GET("/docs", handlers.GetDocs)
+
+	// Let requests presenting the override token select one of the allowed endpoints by alias through the X-Upstream-*
+	// headers, for debugging node-specific discrepancies without a restart. Every allowed endpoint can serve either layer.
+	if cfg.AllowUpstreamOverride {
+		consensusOverrides := make(map[string]handlers.ConsensusProvider, len(cfg.UpstreamOverrideURLs))
+		executionOverrides := make(map[string]handlers.ExecutionProvider, len(cfg.UpstreamOverrideURLs))
+		for alias, endpoint := range cfg.UpstreamOverrideURLs {
+			consensusOverrides[alias] = services.NewConsensusService(endpoint, append([]services.Option{services.WithNetwork(network), services.WithTimeout(cfg.ConsensusTimeout), services.WithHeadSlotTTL(cfg.HeadSlotTTL)}, upstreamOpts...)...)
+			executionOverrides[alias] = services.NewExecutionService(endpoint, append([]services.Option{services.WithTimeout(cfg.ExecutionTimeout), services.WithTransactionHashesOnly(cfg.ExecutionTxHashesOnly)}, upstreamOpts...)...)
+		}
+		handlerOpts = append(handlerOpts, handlers.WithUpstreamOverrides(cfg.UpstreamOverrideToken, consensusOverrides, executionOverrides))
+		log.Printf("ALLOW_UPSTREAM_OVERRIDE enabled for %d endpoints", len(cfg.UpstreamOverrideURLs))
+	}
+
+	// Create a new BlockRewardHandler with the initialized services.
+	blockRewardHandler := handlers.NewBlockRewardHandler(consensusService, executionService, handlerOpts...)
+	metrics.RegisterRewardCache(blockRewardHandler.RewardCacheStats)
+
+	// Register the reward, slot and sync committee endpoints.
+	blockRewardHandler.RegisterRoutes(r)
+
+	// Start the HTTP server on the configured address in the background.
+	// If the server fails to start, log a fatal error and terminate the program.
+	server := &http.Server{
+		Addr:    cfg.Addr,
+		Handler: r,
+	}
+	go func() {
+		log.Printf("Listening on %s", cfg.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// Wait for SIGINT or SIGTERM, then stop accepting connections and let in-flight requests drain.
+	<-ctx.Done()
+
+	log.Printf("Shutting down, waiting up to %s for in-flight requests to complete", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("Forced shutdown: %v", err)
+	}
+	log.Println("Server stopped.")
 }
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"eth-rewards-api/internal/services"
+)
+
+// discoverNetwork returns the network with the epoch length, slot duration and sync committee period length reported
+// in the beacon node's spec, so that the service is correct on networks whose constants differ from the defaults.
+// When the spec is unavailable or incomplete, the network's defaults are kept and a warning is logged.
+func discoverNetwork(network services.NetworkConfig, consensusService *services.ConsensusService, timeout time.Duration) services.NetworkConfig {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	spec, err := consensusService.GetSpec(ctx)
+	if err != nil {
+		log.Printf("Warning: beacon spec unavailable, using the %s defaults: %v", network.Name, err)
+		return network
+	}
+	discovered, err := network.WithSpec(spec)
+	if err != nil {
+		log.Printf("Warning: invalid beacon spec, using the %s defaults: %v", network.Name, err)
+		return network
+	}
+	log.Printf("Discovered network constants: %d slots per epoch, %ds per slot, %d epochs per sync committee period",
+		discovered.SlotsPerEpoch, discovered.SecondsPerSlot, discovered.SyncPeriodEpochs)
+	return discovered
+}
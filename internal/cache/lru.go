@@ -0,0 +1,89 @@
+// The `cache` package provides a bounded, concurrency-safe in-memory cache with least-recently-used eviction.
+// It keeps hit and miss counters so that its effectiveness can be observed.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// LRU is a fixed-capacity cache that evicts the least recently used entry when it is full.
+// It is safe for concurrent use.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	order    *list.List // Front is the most recently used entry.
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// entry is the value stored in each element of the recency list.
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRU initializes a new LRU cache holding at most capacity entries.
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value stored for key and marks it as recently used.
+// The boolean result reports whether the key was present.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		c.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	c.hits.Add(1)
+	c.order.MoveToFront(elem)
+	return elem.Value.(*entry[K, V]).value, true
+}
+
+// Add stores value for key, evicting the least recently used entry if the cache is full.
+func (c *LRU[K, V]) Add(key K, value V) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*entry[K, V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[K, V]).key)
+	}
+}
+
+// Len returns the number of entries currently in the cache.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the number of lookups that found and did not find their key.
+func (c *LRU[K, V]) Stats() (hits, misses uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
@@ -0,0 +1,460 @@
+// The `config` package loads the service configuration from environment variables.
+// It validates every setting up front so that misconfiguration is reported before the server starts.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"eth-rewards-api/internal/version"
+)
+
+// defaultPort is the port the HTTP server listens on when neither SERVER_ADDR nor PORT is set.
+const defaultPort = "8080"
+
+// maxRetryAttempts bounds UPSTREAM_RETRY_MAX_ATTEMPTS. With exponential backoff, more attempts would only keep a
+// request waiting for minutes on an upstream that is down.
+const maxRetryAttempts = 10
+
+// Config holds the settings the service is started with.
+type Config struct {
+	ConsensusEndpoint string   // The URL of the beacon node serving the consensus layer APIs.
+	ExecutionEndpoint string   // The URL of the execution client serving the JSON-RPC APIs.
+	RelayURLs         []string // The URLs of MEV-Boost relays whose data APIs report delivered payloads; empty disables relay data.
+	Addr              string   // The address the HTTP server listens on, in host:port form.
+
+	RetryMaxAttempts int           // The total number of attempts made for each upstream request.
+	RetryBaseDelay   time.Duration // The delay before the first retry of a failed upstream request.
+
+	RewardCacheSize int // The number of finalized slot rewards kept in memory; 0 disables the cache.
+
+	ShutdownTimeout time.Duration // How long in-flight requests may take to complete after a shutdown signal.
+
+	Network        string // The name of the network served by the endpoints, e.g. "mainnet", "holesky" or "sepolia".
+	GenesisTime    int64  // The Unix time of the beacon chain genesis; 0 keeps the network default.
+	SecondsPerSlot int    // The slot duration in seconds; 0 keeps the network default.
+	MergeSlot      int64  // The first slot with an execution payload; -1 keeps the network default.
+
+	LogLevel slog.Level // The minimum level of the log records written, e.g. debug, info, warn or error.
+
+	APIKeys []string // The keys accepted in the X-API-Key header; empty disables authentication.
+
+	RateLimitRPS   float64 // The sustained number of requests per second allowed per client; 0 disables rate limiting.
+	RateLimitBurst int     // The number of requests a client may send in a burst above the sustained rate.
+
+	CORSOrigins []string // The origins browsers may call the API from, or "*" for any; empty keeps the same-origin policy.
+
+	CompressionEnabled bool // Whether responses are gzip-compressed for clients accepting it.
+	CompressionMinSize int  // The size in bytes a response must reach before it is compressed.
+
+	HTTPMaxIdleConns        int           // The maximum number of idle upstream connections kept across all hosts.
+	HTTPMaxIdleConnsPerHost int           // The maximum number of idle upstream connections kept per host.
+	HTTPMaxConnsPerHost     int           // The maximum number of upstream connections per host; 0 means no limit.
+	HTTPIdleConnTimeout     time.Duration // How long an idle upstream connection is kept open.
+
+	ConsensusTimeout time.Duration // The time limit for each request to the consensus endpoint.
+	ExecutionTimeout time.Duration // The time limit for each request to the execution endpoint.
+
+	HeadSlotTTL time.Duration // How long the head slot is served from memory; 0 disables caching.
+
+	DebugUpstream bool // Log every upstream request and response, with API keys masked in URLs.
+
+	MaxUpstreamConcurrency int // The maximum number of upstream requests in flight across both services; 0 means no limit.
+
+	UserAgent string // The User-Agent header sent with every upstream request.
+
+	ExecutionLagRetryAttempts int           // The retries made when the execution client does not have a beacon block's execution block yet.
+	ExecutionLagRetryDelay    time.Duration // The delay before each of those retries.
+
+	ExecutionTxHashesOnly bool // Fetch execution blocks with transaction hashes only and read transaction details from receipts.
+
+	StrictNetworkCheck bool // Refuse to start when the execution client's chain ID does not match the network.
+
+	AllowUpstreamOverride bool              // Whether requests may select their upstream endpoints through the X-Upstream-* headers.
+	UpstreamOverrideURLs  map[string]string // The endpoints requests may select when overrides are allowed, keyed by alias.
+	UpstreamOverrideToken string            // The credential a request must present to select an upstream endpoint.
+}
+
+// Load reads the configuration from the environment and validates it.
+// It returns an error describing the first invalid or missing setting.
+func Load() (*Config, error) {
+	consensusEndpoint, executionEndpoint, err := endpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := serverAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	retryMaxAttempts, err := envInt("UPSTREAM_RETRY_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+	if retryMaxAttempts < 1 || retryMaxAttempts > maxRetryAttempts {
+		return nil, fmt.Errorf("UPSTREAM_RETRY_MAX_ATTEMPTS must be between 1 and %d", maxRetryAttempts)
+	}
+	retryBaseDelay, err := envDuration("UPSTREAM_RETRY_BASE_DELAY", 200*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+
+	rewardCacheSize, err := envInt("REWARD_CACHE_SIZE", 1024)
+	if err != nil {
+		return nil, err
+	}
+	if rewardCacheSize < 0 {
+		return nil, errors.New("REWARD_CACHE_SIZE must not be negative")
+	}
+
+	shutdownTimeout, err := envDuration("SHUTDOWN_TIMEOUT", 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	network := os.Getenv("NETWORK")
+	if network == "" {
+		network = "mainnet"
+	}
+	genesisTime, err := envInt("GENESIS_TIME", 0)
+	if err != nil {
+		return nil, err
+	}
+	if genesisTime < 0 {
+		return nil, errors.New("GENESIS_TIME must not be negative")
+	}
+	secondsPerSlot, err := envInt("SECONDS_PER_SLOT", 0)
+	if err != nil {
+		return nil, err
+	}
+	if secondsPerSlot < 0 {
+		return nil, errors.New("SECONDS_PER_SLOT must not be negative")
+	}
+	mergeSlot, err := envInt("MERGE_SLOT", -1)
+	if err != nil {
+		return nil, err
+	}
+	if mergeSlot < -1 {
+		return nil, errors.New("MERGE_SLOT must not be negative")
+	}
+
+	var logLevel slog.Level
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := logLevel.UnmarshalText([]byte(value)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL %q: must be debug, info, warn or error", value)
+		}
+	}
+
+	rateLimitRPS, err := envFloat("RATE_LIMIT_RPS", 0)
+	if err != nil {
+		return nil, err
+	}
+	if rateLimitRPS < 0 {
+		return nil, errors.New("RATE_LIMIT_RPS must not be negative")
+	}
+	// Default the burst to one second worth of requests.
+	rateLimitBurst, err := envInt("RATE_LIMIT_BURST", int(math.Max(1, math.Ceil(rateLimitRPS))))
+	if err != nil {
+		return nil, err
+	}
+	if rateLimitBurst < 1 {
+		return nil, errors.New("RATE_LIMIT_BURST must be at least 1")
+	}
+
+	compressionEnabled, err := envBool("COMPRESSION_ENABLED", true)
+	if err != nil {
+		return nil, err
+	}
+	compressionMinSize, err := envInt("COMPRESSION_MIN_SIZE", 1024)
+	if err != nil {
+		return nil, err
+	}
+	if compressionMinSize < 0 {
+		return nil, errors.New("COMPRESSION_MIN_SIZE must not be negative")
+	}
+
+	httpMaxIdleConns, err := envInt("HTTP_MAX_IDLE_CONNS", 100)
+	if err != nil {
+		return nil, err
+	}
+	httpMaxIdleConnsPerHost, err := envInt("HTTP_MAX_IDLE_CONNS_PER_HOST", 32)
+	if err != nil {
+		return nil, err
+	}
+	httpMaxConnsPerHost, err := envInt("HTTP_MAX_CONNS_PER_HOST", 0)
+	if err != nil {
+		return nil, err
+	}
+	if httpMaxIdleConns < 0 || httpMaxIdleConnsPerHost < 0 || httpMaxConnsPerHost < 0 {
+		return nil, errors.New("HTTP_MAX_IDLE_CONNS, HTTP_MAX_IDLE_CONNS_PER_HOST and HTTP_MAX_CONNS_PER_HOST must not be negative")
+	}
+	maxUpstreamConcurrency, err := envInt("MAX_UPSTREAM_CONCURRENCY", 0)
+	if err != nil {
+		return nil, err
+	}
+	if maxUpstreamConcurrency < 0 {
+		return nil, errors.New("MAX_UPSTREAM_CONCURRENCY must not be negative")
+	}
+
+	httpIdleConnTimeout, err := envDuration("HTTP_IDLE_CONN_TIMEOUT", 90*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execution requests fetch whole blocks with their transactions and receipts, so they get more time by default.
+	consensusTimeout, err := envDuration("CONSENSUS_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if consensusTimeout == 0 {
+		return nil, errors.New("CONSENSUS_TIMEOUT must be greater than zero")
+	}
+	executionTimeout, err := envDuration("EXECUTION_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if executionTimeout == 0 {
+		return nil, errors.New("EXECUTION_TIMEOUT must be greater than zero")
+	}
+
+	headSlotTTL, err := envDuration("HEAD_SLOT_TTL", 4*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	debugUpstream, err := envBool("DEBUG_UPSTREAM", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The execution client usually imports a new block within a second of the beacon node.
+	executionLagRetryAttempts, err := envInt("EXECUTION_LAG_RETRY_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+	if executionLagRetryAttempts < 0 {
+		return nil, errors.New("EXECUTION_LAG_RETRY_ATTEMPTS must not be negative")
+	}
+	executionLagRetryDelay, err := envDuration("EXECUTION_LAG_RETRY_DELAY", 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+
+	executionTxHashesOnly, err := envBool("EXECUTION_TX_HASHES_ONLY", false)
+	if err != nil {
+		return nil, err
+	}
+
+	strictNetworkCheck, err := envBool("STRICT_NETWORK_CHECK", false)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := os.Getenv("USER_AGENT")
+	if userAgent == "" {
+		userAgent = version.UserAgent()
+	}
+
+	allowUpstreamOverride, err := envBool("ALLOW_UPSTREAM_OVERRIDE", false)
+	if err != nil {
+		return nil, err
+	}
+	upstreamOverrideURLs, err := envAliases("UPSTREAM_OVERRIDE_URLS")
+	if err != nil {
+		return nil, err
+	}
+	upstreamOverrideToken := os.Getenv("UPSTREAM_OVERRIDE_TOKEN")
+	if allowUpstreamOverride && len(upstreamOverrideURLs) == 0 {
+		return nil, errors.New("UPSTREAM_OVERRIDE_URLS must list the endpoints requests may select when ALLOW_UPSTREAM_OVERRIDE is set")
+	}
+	if allowUpstreamOverride && upstreamOverrideToken == "" {
+		return nil, errors.New("UPSTREAM_OVERRIDE_TOKEN must be set when ALLOW_UPSTREAM_OVERRIDE is set")
+	}
+
+	return &Config{
+		ConsensusEndpoint:  consensusEndpoint,
+		ExecutionEndpoint:  executionEndpoint,
+		RelayURLs:          envList("RELAY_URLS"),
+		Addr:               addr,
+		RetryMaxAttempts:   retryMaxAttempts,
+		RetryBaseDelay:     retryBaseDelay,
+		RewardCacheSize:    rewardCacheSize,
+		ShutdownTimeout:    shutdownTimeout,
+		Network:            network,
+		GenesisTime:        int64(genesisTime),
+		SecondsPerSlot:     secondsPerSlot,
+		MergeSlot:          int64(mergeSlot),
+		LogLevel:           logLevel,
+		APIKeys:            envList("API_KEYS"),
+		RateLimitRPS:       rateLimitRPS,
+		RateLimitBurst:     rateLimitBurst,
+		CORSOrigins:        envList("CORS_ORIGINS"),
+		CompressionEnabled: compressionEnabled,
+		CompressionMinSize: compressionMinSize,
+
+		HTTPMaxIdleConns:        httpMaxIdleConns,
+		HTTPMaxIdleConnsPerHost: httpMaxIdleConnsPerHost,
+		HTTPMaxConnsPerHost:     httpMaxConnsPerHost,
+		HTTPIdleConnTimeout:     httpIdleConnTimeout,
+
+		ConsensusTimeout: consensusTimeout,
+		ExecutionTimeout: executionTimeout,
+
+		HeadSlotTTL: headSlotTTL,
+
+		DebugUpstream: debugUpstream,
+
+		MaxUpstreamConcurrency: maxUpstreamConcurrency,
+
+		UserAgent: userAgent,
+
+		ExecutionLagRetryAttempts: executionLagRetryAttempts,
+		ExecutionLagRetryDelay:    executionLagRetryDelay,
+
+		ExecutionTxHashesOnly: executionTxHashesOnly,
+
+		StrictNetworkCheck: strictNetworkCheck,
+
+		AllowUpstreamOverride: allowUpstreamOverride,
+		UpstreamOverrideURLs:  upstreamOverrideURLs,
+		UpstreamOverrideToken: upstreamOverrideToken,
+	}, nil
+}
+
+// envInt reads an integer environment variable, returning def when it is not set.
+func envInt(name string, def int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be an integer", name, value)
+	}
+	return n, nil
+}
+
+// envBool reads a boolean environment variable such as "true" or "false", returning def when it is not set.
+func envBool(name string, def bool) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: must be true or false", name, value)
+	}
+	return b, nil
+}
+
+// envFloat reads a floating-point environment variable, returning def when it is not set.
+func envFloat(name string, def float64) (float64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid %s %q: must be a number", name, value)
+	}
+	return f, nil
+}
+
+// envDuration reads a duration environment variable such as "500ms" or "10s", returning def when it is not set.
+func envDuration(name string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative duration such as 500ms or 10s", name, value)
+	}
+	return d, nil
+}
+
+// envList reads a comma-separated environment variable, dropping surrounding whitespace and empty entries.
+func envList(name string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// envAliases reads a comma-separated environment variable of alias=URL entries into a map keyed by alias.
+func envAliases(name string) (map[string]string, error) {
+	list := envList(name)
+	aliases := make(map[string]string, len(list))
+	for _, item := range list {
+		alias, endpoint, ok := strings.Cut(item, "=")
+		alias, endpoint = strings.TrimSpace(alias), strings.TrimSpace(endpoint)
+		if !ok || alias == "" || endpoint == "" {
+			// The entry is not echoed, as its URL may carry a provider token.
+			return nil, fmt.Errorf("invalid %s entry: every entry must be alias=URL", name)
+		}
+		if _, dup := aliases[alias]; dup {
+			return nil, fmt.Errorf("invalid %s: alias %q is listed more than once", name, alias)
+		}
+		aliases[alias] = endpoint
+	}
+	return aliases, nil
+}
+
+// endpoints resolves the consensus and execution endpoints from CONSENSUS_ENDPOINT and EXECUTION_ENDPOINT.
+// Either one falls back to QUICKNODE_ENDPOINT, which serves both layers, when it is not set.
+func endpoints() (string, string, error) {
+	combined := os.Getenv("QUICKNODE_ENDPOINT")
+	consensus := os.Getenv("CONSENSUS_ENDPOINT")
+	if consensus == "" {
+		consensus = combined
+	}
+	execution := os.Getenv("EXECUTION_ENDPOINT")
+	if execution == "" {
+		execution = combined
+	}
+
+	switch {
+	case consensus == "" && execution == "":
+		return "", "", errors.New("no upstream endpoint configured: set QUICKNODE_ENDPOINT, or both CONSENSUS_ENDPOINT and EXECUTION_ENDPOINT")
+	case consensus == "":
+		return "", "", errors.New("CONSENSUS_ENDPOINT environment variable not set and no QUICKNODE_ENDPOINT to fall back to")
+	case execution == "":
+		return "", "", errors.New("EXECUTION_ENDPOINT environment variable not set and no QUICKNODE_ENDPOINT to fall back to")
+	}
+	return consensus, execution, nil
+}
+
+// serverAddr resolves the listen address from SERVER_ADDR (host:port) or PORT, defaulting to port 8080.
+func serverAddr() (string, error) {
+	addr := os.Getenv("SERVER_ADDR")
+	if addr == "" {
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = defaultPort
+		}
+		addr = ":" + port
+	}
+
+	// Ensure the address carries a legal port before the server tries to bind it.
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid server address %q: %w", addr, err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil || portNumber < 1 || portNumber > 65535 {
+		return "", fmt.Errorf("invalid server port %q: must be between 1 and 65535", port)
+	}
+	return addr, nil
+}
@@ -0,0 +1,30 @@
+// The `docs` package embeds the OpenAPI description of the API and the page rendering it with Swagger UI.
+// The specification is maintained by hand in openapi.json; update it together with the handlers and models it describes.
+
+package docs
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI 3 specification of the API, in JSON.
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
+
+// SwaggerUIPage is an HTML page rendering the specification served at /openapi.json with Swagger UI.
+// The Swagger UI assets are loaded from a public CDN, so the page needs internet access in the browser.
+const SwaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Ethereum Rewards API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
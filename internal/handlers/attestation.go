@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAttestationRewards handles HTTP requests to retrieve the attestation rewards earned by validators during an epoch.
+// The optional validators query parameter restricts the result to a comma-separated list of validator indices.
+func (h *BlockRewardHandler) GetAttestationRewards(c *gin.Context) {
+	// Parse the epoch parameter from the request URL.
+	epoch, err := utils.ParseUint(c.Param("epoch"))
+	if err != nil {
+		respondInvalidInput(c, "epoch", err)
+		return
+	}
+
+	// Parse the optional list of validator indices.
+	var validators []string
+	if validatorsParam := c.Query("validators"); validatorsParam != "" {
+		for _, index := range strings.Split(validatorsParam, ",") {
+			index = strings.TrimSpace(index)
+			if _, err := strconv.ParseUint(index, 10, 64); err != nil {
+				utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidParameter, "invalid validators parameter")
+				return
+			}
+			validators = append(validators, index)
+		}
+	}
+
+	// Ensure the requested epoch is not in the future by comparing it with the epoch of the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if epoch > headSlot/h.consensusService.SlotsPerEpoch() {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeEpochInFuture, "requested epoch is in the future")
+		return
+	}
+
+	// Retrieve the attestation rewards for the specified epoch.
+	rewards, err := h.consensusService.GetAttestationRewards(c.Request.Context(), epoch, validators)
+	if err != nil {
+		if errors.Is(err, services.ErrAttestationRewardsNotFound) {
+			utils.RespondError(c, http.StatusNotFound, utils.CodeNotFound, "attestation rewards not found")
+			return
+		}
+		respondError(c, upstreamError("failed to get attestation rewards", err))
+		return
+	}
+
+	// Respond with the per-validator reward components.
+	c.JSON(http.StatusOK, gin.H{
+		"epoch":   epoch,
+		"rewards": rewards.Data.TotalRewards,
+	})
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBatchSlots caps the number of slots accepted by a single batch request.
+const maxBatchSlots = 100
+
+// batchWorkers bounds the number of slots whose rewards are computed concurrently.
+const batchWorkers = 8
+
+// GetBlockRewardBatch handles HTTP requests to retrieve the block rewards for several slots at once.
+// Each slot is reported individually, so a failing slot does not fail the whole request.
+// Clients sending "Accept: text/csv" receive the results as CSV instead of JSON.
+func (h *BlockRewardHandler) GetBlockRewardBatch(c *gin.Context) {
+	// Parse the list of slots from the request body.
+	var req models.BatchRewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidParameter, "invalid request body")
+		return
+	}
+	if len(req.Slots) == 0 {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidParameter, "no slots requested")
+		return
+	}
+	if len(req.Slots) > maxBatchSlots {
+		utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, fmt.Sprintf("too many slots requested (max %d)", maxBatchSlots), gin.H{"max_slots": maxBatchSlots})
+		return
+	}
+
+	// Fetch the head slot once so every slot is checked against the same head.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+
+	// Respond with the per-slot results in the order the slots were requested.
+	results := h.blockRewards(c.Request.Context(), req.Slots, headSlot)
+	if wantsCSV(c) {
+		respondRewardsCSV(c, results)
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// errSlotInFuture is reported for slots of a multi-slot request that are after the current head slot.
+var errSlotInFuture = &requestError{http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is in the future", nil}
+
+// blockRewards computes the rewards for the given slots. The results are returned in the same order as the slots.
+// Beacon blocks are fetched and rewards computed by a bounded pool of workers, while the execution blocks and receipts
+// of all slots are fetched together in batched JSON-RPC calls. Canceling ctx aborts the outstanding upstream calls.
+func (h *BlockRewardHandler) blockRewards(ctx context.Context, slots []uint64, headSlot uint64) []models.SlotRewardResult {
+	rewards := make([]*models.BlockReward, len(slots))
+	errs := make([]error, len(slots))
+
+	// Serve the cached slots and retrieve the beacon blocks of the others.
+	generation := h.rewardGeneration()
+	beaconBlocks := make([]*models.BeaconBlockResponse, len(slots))
+	forEachSlot(len(slots), func(i int) {
+		if slots[i] > headSlot {
+			errs[i] = errSlotInFuture
+			return
+		}
+		if reward, ok := h.cachedReward(ctx, slots[i]); ok {
+			rewards[i] = reward
+			return
+		}
+		beaconBlocks[i], errs[i] = h.fetchBeaconBlock(ctx, slots[i])
+	})
+
+	// Retrieve the execution blocks and receipts of all payloads at once.
+	blocks := h.completeSlotBlocks(ctx, slots, beaconBlocks, errs)
+
+	// Compute the rewards of the fetched blocks.
+	forEachSlot(len(slots), func(i int) {
+		if blocks[i] == nil {
+			return
+		}
+		rewards[i], errs[i] = h.rewardForBlock(ctx, slots[i], blocks[i], modeEffective)
+		if errs[i] == nil {
+			h.storeReward(ctx, slots[i], rewards[i], generation)
+		}
+	})
+
+	results := make([]models.SlotRewardResult, len(slots))
+	for i, slot := range slots {
+		results[i] = slotRewardResult(slot, rewards[i], errs[i])
+	}
+	return results
+}
+
+// completeSlotBlocks retrieves the execution blocks and receipts of the payloads of the beacon blocks of the given slots
+// in batched calls. Nil beacon blocks are skipped. The failure for each beacon block is recorded at its index in errs.
+func (h *BlockRewardHandler) completeSlotBlocks(ctx context.Context, slots []uint64, beaconBlocks []*models.BeaconBlockResponse, errs []error) []*slotBlock {
+	blocks := make([]*slotBlock, len(beaconBlocks))
+
+	// Collect the payload block numbers, remembering which slot each one belongs to.
+	var indexes []int
+	var blockSlots []uint64
+	var blockNumbers []string
+	for i, beaconBlock := range beaconBlocks {
+		if beaconBlock == nil {
+			continue
+		}
+		blockNumberHex, err := payloadBlockNumber(beaconBlock)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		indexes = append(indexes, i)
+		blockSlots = append(blockSlots, slots[i])
+		blockNumbers = append(blockNumbers, blockNumberHex)
+	}
+	if len(blockNumbers) == 0 {
+		return blocks
+	}
+
+	// Wait for a lagging execution client as for a single slot.
+	fetched, err := h.payloadBlocksWithReceipts(ctx, blockSlots, blockNumbers)
+	for j, i := range indexes {
+		switch {
+		case err != nil:
+			errs[i] = upstreamError("failed to get execution blocks", err)
+		case fetched[j].Err != nil:
+			errs[i] = upstreamError("failed to get execution block", fetched[j].Err)
+		default:
+			blocks[i], errs[i] = newSlotBlock(beaconBlocks[i], fetched[j].Block, fetched[j].Receipts)
+		}
+	}
+	return blocks
+}
+
+// forEachSlot calls fn for every index below n with a bounded pool of workers and waits for all calls to return.
+func forEachSlot(n int, fn func(i int)) {
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < batchWorkers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// slotRewardResult builds the result for a single slot, recording any failure in the result instead of returning it.
+func slotRewardResult(slot uint64, reward *models.BlockReward, err error) models.SlotRewardResult {
+	result := models.SlotRewardResult{Slot: slot}
+	if errors.Is(err, errPreMergeSlot) {
+		return preMergeResult(slot)
+	}
+	var reqErr *requestError
+	if errors.As(err, &reqErr) && missingSlotStatuses[reqErr] != "" {
+		// A missed or orphaned slot is a valid outcome, so report it as a status rather than an error.
+		result.BlockReward = &models.BlockReward{Status: missingSlotStatuses[reqErr]}
+		return result
+	}
+	if err != nil {
+		result.Error = err.Error()
+		result.Code = errorCode(err)
+		return result
+	}
+	result.BlockReward = reward
+	return result
+}
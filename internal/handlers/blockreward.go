@@ -2,174 +2,943 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"eth-rewards-api/internal/models"
 	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// BlockRewardHandler is a struct that holds references to the consensus and execution services.
+// BlockRewardHandler is a struct that holds references to the consensus and execution-layer providers.
+// It optionally caches the rewards of finalized slots.
 type BlockRewardHandler struct {
-	consensusService *services.ConsensusService
-	executionService *services.ExecutionService
+	consensusService ConsensusProvider
+	executionService ExecutionProvider
+	relayService     RelayProvider // Reports the payloads delivered by MEV-Boost relays; nil when no relays are configured.
+	rewardCache      *rewardCache
+	allowedOrigins   map[string]bool    // The origins allowed to open reward streams besides the API's own.
+	headFeed         *headFeed          // Distributes head events to reward streams; nil when the head slot is polled instead.
+	overrides        *upstreamOverrides // The endpoints requests may select through the override headers; nil when overrides are disabled.
+	executionLag     executionLagRetry  // How lookups of execution blocks the execution client does not have yet are retried.
 }
 
-// NewBlockRewardHandler initializes a new BlockRewardHandler with the provided services.
-func NewBlockRewardHandler(cs *services.ConsensusService, es *services.ExecutionService) *BlockRewardHandler {
-	return &BlockRewardHandler{
+// HandlerOption configures optional behaviour of the BlockRewardHandler.
+type HandlerOption func(*BlockRewardHandler)
+
+// WithRewardCache enables an LRU cache holding the rewards of up to size finalized slots.
+// A size of zero or less leaves caching disabled.
+func WithRewardCache(size int) HandlerOption {
+	return func(h *BlockRewardHandler) {
+		if size > 0 {
+			h.rewardCache = newRewardCache(size)
+		}
+	}
+}
+
+// WithRelays enriches block rewards with the relays, builder and bid value reported by the MEV-Boost relays of rp.
+func WithRelays(rp RelayProvider) HandlerOption {
+	return func(h *BlockRewardHandler) {
+		h.relayService = rp
+	}
+}
+
+// NewBlockRewardHandler initializes a new BlockRewardHandler with the provided consensus and execution-layer providers,
+// usually a *services.ConsensusService and a *services.ExecutionService.
+func NewBlockRewardHandler(cs ConsensusProvider, es ExecutionProvider, opts ...HandlerOption) *BlockRewardHandler {
+	h := &BlockRewardHandler{
 		consensusService: cs,
 		executionService: es,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// RewardCacheStats returns the hit and miss counts of the reward cache, or zeros when caching is disabled.
+func (h *BlockRewardHandler) RewardCacheStats() (hits, misses uint64) {
+	if h.rewardCache == nil {
+		return 0, 0
+	}
+	return h.rewardCache.lru.Stats()
+}
+
+// slotAliases lists the named block identifiers accepted in place of a numeric slot.
+var slotAliases = map[string]bool{
+	"head":      true,
+	"finalized": true,
+	"justified": true,
+}
+
+// resolveSlotParam parses the slot path parameter, given in decimal or as 0x-prefixed hex, resolving the
+// head/finalized/justified aliases through the consensus layer.
+// It writes the error response itself and returns false when the slot cannot be determined.
+func (h *BlockRewardHandler) resolveSlotParam(c *gin.Context) (uint64, bool) {
+	slotParam := strings.ToLower(strings.TrimSpace(c.Param("slot")))
+	if slotAliases[slotParam] {
+		slot, err := h.consensusService.ResolveSlot(c.Request.Context(), slotParam)
+		if err != nil {
+			respondError(c, upstreamError("failed to resolve slot alias", err))
+			return 0, false
+		}
+		return slot, true
+	}
+
+	slot, err := utils.ParseUint(c.Param("slot"))
+	if err != nil {
+		respondInvalidInput(c, "slot", err)
+		return 0, false
+	}
+	return slot, true
+}
+
+// requestError pairs an HTTP status code and error code with the client-facing message for a failed lookup.
+// The underlying cause, if any, is logged but never sent to the client.
+type requestError struct {
+	status  int
+	code    string
+	message string
+	err     error
+}
+
+// Error returns the client-facing message of the request error.
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the underlying cause of the request error.
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// internalError returns a request error answering with a 500 status and the given message, caused by err.
+func internalError(message string, err error) *requestError {
+	return &requestError{http.StatusInternalServerError, utils.CodeInternalError, message, err}
+}
+
+// upstreamError returns a request error answering with the given message, caused by a failed upstream call.
+// Calls that timed out are answered with a 504 status, since retrying them may succeed, and other failures with a 500 status.
+func upstreamError(message string, err error) *requestError {
+	if isTimeout(err) {
+		return &requestError{http.StatusGatewayTimeout, utils.CodeUpstreamTimeout, message, err}
+	}
+	return &requestError{http.StatusInternalServerError, utils.CodeUpstreamError, message, err}
+}
+
+// isTimeout reports whether err was caused by an upstream call exceeding its deadline or the client's timeout.
+func isTimeout(err error) bool {
+	var timeoutErr interface{ Timeout() bool }
+	return errors.Is(err, context.DeadlineExceeded) || os.IsTimeout(err) || errors.As(err, &timeoutErr) && timeoutErr.Timeout()
+}
+
+// errorCode returns the error code describing err to clients.
+func errorCode(err error) string {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.code
+	}
+	return utils.CodeInternalError
+}
+
+// errSlotMissed is returned when no beacon block was proposed for the requested slot.
+var errSlotMissed = &requestError{http.StatusNotFound, utils.CodeSlotMissed, "slot not found/missed", nil}
+
+// errSlotOrphaned is returned when a block was proposed for the requested slot but was reorged out of the canonical chain.
+var errSlotOrphaned = &requestError{http.StatusNotFound, utils.CodeSlotOrphaned, "block for this slot was orphaned", nil}
+
+// errPreGenesis is returned while the configured network has not reached its genesis, so no slot can hold a block yet.
+var errPreGenesis = &requestError{http.StatusNotFound, utils.CodePreGenesis, "network has not reached genesis yet", nil}
+
+// errPreMergeSlot is returned for slots before the merge, whose blocks carry no execution payload.
+// The block reward endpoints answer it with preMergeResult rather than as an error.
+var errPreMergeSlot = &requestError{http.StatusBadRequest, utils.CodePreMergeSlot, "slot is before the merge and has no execution payload", nil}
+
+// preMergeResult returns the result for a slot before the merge. Its block earns no execution-layer reward, so like
+// GetProposerReward the slot is reported with a null reward and the PRE_MERGE_SLOT code rather than as an error.
+func preMergeResult(slot uint64) models.SlotRewardResult {
+	return models.SlotRewardResult{Slot: slot, BlockReward: &models.BlockReward{Status: "pre_merge"}, Code: utils.CodePreMergeSlot}
+}
+
+// missingSlotStatuses maps the errors describing a slot without a canonical block to the status reported for it
+// in multi-slot responses, where such slots are valid outcomes rather than failures.
+var missingSlotStatuses = map[*requestError]string{
+	errSlotMissed:   "missed",
+	errSlotOrphaned: "orphaned",
+}
+
+// classifyMissingSlot explains why the beacon node has no canonical block for a slot.
+// The slot is pre-genesis while the network has not started, orphaned when the node knows a non-canonical block for it,
+// and missed otherwise. If the headers cannot be retrieved, the slot is reported as missed.
+func (h *BlockRewardHandler) classifyMissingSlot(ctx context.Context, slot uint64) *requestError {
+	if time.Now().Before(h.consensusService.Network().Genesis()) {
+		return errPreGenesis
+	}
+
+	headers, err := h.consensusService.GetBlockHeadersBySlot(ctx, slot)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to classify slot without a block", "slot", slot, "error", err)
+		return errSlotMissed
+	}
+	for _, header := range headers.Data {
+		if !header.Canonical {
+			return errSlotOrphaned
+		}
+	}
+	return errSlotMissed
+}
+
+// respondError writes the JSON error response for an error returned by the reward computation.
+func respondError(c *gin.Context, err error) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		if reqErr.status >= http.StatusInternalServerError {
+			slog.ErrorContext(c.Request.Context(), reqErr.message, "error", reqErr.err)
+		}
+		utils.RespondError(c, reqErr.status, reqErr.code, reqErr.message)
+		return
+	}
+	slog.ErrorContext(c.Request.Context(), "internal error", "error", err)
+	utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternalError, "internal error")
+}
+
+// respondInvalidInput writes the INVALID_INPUT response for a parameter whose value could not be parsed.
+// The details name the parameter and the offending value, so clients can tell which input was rejected.
+func respondInvalidInput(c *gin.Context, param string, err error) {
+	details := gin.H{"parameter": param}
+	message := fmt.Sprintf("invalid %s parameter", param)
+	var inputErr *utils.InputError
+	if errors.As(err, &inputErr) {
+		details["value"] = inputErr.Value
+		message += ": " + inputErr.Reason
+	}
+	utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidInput, message, details)
 }
 
 // GetBlockReward handles HTTP requests to retrieve the block reward for a given slot.
 func (h *BlockRewardHandler) GetBlockReward(c *gin.Context) {
 	// Parse the slot parameter from the request URL.
-	slotParam := c.Param("slot")
-	slot, err := strconv.ParseUint(slotParam, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid slot parameter"})
+	slot, ok := h.resolveSlotParam(c)
+	if !ok {
+		return
+	}
+
+	// Parse the unit the reward should be expressed in, defaulting to gwei.
+	unit, ok := parseUnitParam(c)
+	if !ok {
 		return
 	}
 
+	// Parse how the transaction rewards are attributed, defaulting to the effective gas price.
+	mode, ok := parseModeParam(c)
+	if !ok {
+		return
+	}
+
+	// Parse the optional comma-separated list of fields to return, which defaults to all of them.
+	fields, ok := parseFieldsParam(c, blockRewardFields)
+	if !ok {
+		return
+	}
+
+	// Parse the optional comma-separated list of fee recipients the block is expected to pay.
+	var expectedRecipients []string
+	if expectedParam := c.Query("expected_recipient"); expectedParam != "" {
+		for _, address := range strings.Split(expectedParam, ",") {
+			address, err := utils.ParseHexData(address, 20)
+			if err != nil {
+				respondInvalidInput(c, "expected_recipient", err)
+				return
+			}
+			expectedRecipients = append(expectedRecipients, address)
+		}
+	}
+
 	// Ensure the requested slot is not in the future by comparing it with the current head slot.
-	headSlot, err := h.consensusService.GetHeadSlot()
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch head slot"})
+		respondError(c, upstreamError("failed to fetch head slot", err))
 		return
 	}
 	if slot > headSlot {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "requested slot is in the future"})
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is in the future")
 		return
 	}
 
-	// Retrieve the beacon block for the specified slot.
-	beaconBlock, err := h.consensusService.GetBeaconBlockBySlot(slot)
+	// A slot requested by number rather than through an alias always refers to the same block.
+	fixed := !slotAliases[strings.ToLower(strings.TrimSpace(c.Param("slot")))]
+
+	reward, err := h.blockRewardInMode(c.Request.Context(), slot, mode)
+	if errors.Is(err, errPreMergeSlot) {
+		h.respondSlotData(c, slot, fixed, preMergeResult(slot))
+		return
+	} else if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Express the reward in the requested unit on a copy, since the computed reward may be shared through the cache.
+	resp, err := rewardInUnit(reward, unit)
 	if err != nil {
-		if err.Error() == "block not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "slot not found/missed"})
+		respondError(c, internalError("invalid reward", err))
+		return
+	}
+
+	// Place the slot within its epoch, whose length depends on the network.
+	slotsPerEpoch := h.consensusService.SlotsPerEpoch()
+	epoch, slotInEpoch := slot/slotsPerEpoch, slot%slotsPerEpoch
+	resp.Epoch = &epoch
+	resp.SlotInEpoch = &slotInEpoch
+
+	// Resolve the proposer's public key when requested.
+	if c.Query("pubkeys") == "true" && resp.ProposerIndex != "" {
+		pubkeys, err := h.consensusService.GetValidatorPubkeys(c.Request.Context(), []string{resp.ProposerIndex})
+		if err != nil {
+			respondError(c, upstreamError("failed to resolve proposer public key", err))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get beacon block"})
+		resp.ProposerPubkey = pubkeys[resp.ProposerIndex]
+	}
+
+	// Report whether the fee recipient is one of the expected ones when requested. Addresses are compared case-insensitively,
+	// so checksummed and lowercase addresses match.
+	if expectedRecipients != nil {
+		matches := false
+		for _, address := range expectedRecipients {
+			if strings.EqualFold(address, resp.FeeRecipient) {
+				matches = true
+				break
+			}
+		}
+		resp.RecipientMatches = &matches
+	}
+
+	// Reduce the response to the requested fields.
+	body, err := projectFields(resp, fields)
+	if err != nil {
+		respondError(c, internalError("failed to encode response", err))
 		return
 	}
 
+	// Respond with the calculated reward and status, cacheable for good once the slot is finalized unless the reward is partial.
+	h.respondSlotData(c, slot, fixed && !reward.Partial, body)
+}
+
+// blockReward returns the proposer reward for a slot that is known not to be in the future.
+// Rewards are served from and stored in the reward cache when it is enabled; see rewardCache for how recent slots are kept safe.
+// The returned value may be shared with other requests and must not be modified.
+func (h *BlockRewardHandler) blockReward(ctx context.Context, slot uint64) (*models.BlockReward, error) {
+	if reward, ok := h.cachedReward(ctx, slot); ok {
+		return reward, nil
+	}
+
+	generation := h.rewardGeneration()
+	reward, err := h.computeBlockReward(ctx, slot, modeEffective)
+	if err != nil {
+		return nil, err
+	}
+	h.storeReward(ctx, slot, reward, generation)
+	return reward, nil
+}
+
+// cachedReward returns the reward of a slot from the reward cache, if the cache is enabled and holds it.
+func (h *BlockRewardHandler) cachedReward(ctx context.Context, slot uint64) (*models.BlockReward, bool) {
+	if h.rewardCache == nil {
+		return nil, false
+	}
+	return h.rewardCache.get(ctx, h.consensusService, slot)
+}
+
+// rewardGeneration returns the generation of the reward cache to pass to storeReward, taken before computing a reward.
+func (h *BlockRewardHandler) rewardGeneration() uint64 {
+	if h.rewardCache == nil {
+		return 0
+	}
+	return h.rewardCache.generation()
+}
+
+// storeReward adds the reward of a slot to the reward cache if the cache is enabled, unless a reorg was detected since
+// generation was taken. Partial rewards are not stored, so that the lookups that failed are retried by the next request.
+func (h *BlockRewardHandler) storeReward(ctx context.Context, slot uint64, reward *models.BlockReward, generation uint64) {
+	if h.rewardCache != nil && !reward.Partial {
+		h.rewardCache.store(ctx, h.consensusService, slot, reward, generation)
+	}
+}
+
+// slotBlock bundles the beacon block of a slot with the execution block and receipts of its payload.
+type slotBlock struct {
+	beacon   *models.BeaconBlockResponse
+	exec     *models.ExecutionBlockFullResponse
+	receipts []models.TransactionReceipt // The receipts of the execution block's transactions, in the same order.
+	baseFee  *big.Int                    // The base fee per gas of the execution block.
+}
+
+// fetchSlotBlock retrieves the beacon block of a slot together with the execution block and receipts of its payload.
+// Failures are returned as *requestError values describing the response to send to the client.
+func (h *BlockRewardHandler) fetchSlotBlock(ctx context.Context, slot uint64) (*slotBlock, error) {
+	beaconBlock, err := h.fetchBeaconBlock(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	return h.completeSlotBlock(ctx, slot, beaconBlock)
+}
+
+// fetchBeaconBlock retrieves the beacon block of a post-merge slot.
+// Failures are returned as *requestError values describing the response to send to the client.
+func (h *BlockRewardHandler) fetchBeaconBlock(ctx context.Context, slot uint64) (*models.BeaconBlockResponse, error) {
+	// Reject slots before the merge up front rather than reporting a confusing missing payload.
+	if h.consensusService.Network().IsPreMerge(slot) {
+		return nil, errPreMergeSlot
+	}
+
+	// Retrieve the beacon block for the specified slot.
+	beaconBlock, err := h.consensusService.GetBeaconBlockBySlot(ctx, slot)
+	if err != nil {
+		if errors.Is(err, services.ErrBlockNotFound) {
+			return nil, h.classifyMissingSlot(ctx, slot)
+		}
+		return nil, upstreamError("failed to get beacon block", err)
+	}
+	return beaconBlock, nil
+}
+
+// completeSlotBlock retrieves the execution block and receipts of the execution payload of the beacon block of a slot.
+// Failures are returned as *requestError values describing the response to send to the client.
+func (h *BlockRewardHandler) completeSlotBlock(ctx context.Context, slot uint64, beaconBlock *models.BeaconBlockResponse) (*slotBlock, error) {
+	blockNumberHex, err := payloadBlockNumber(beaconBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve the execution block using the block number in hexadecimal format, waiting for a lagging execution client.
+	execBlock, err := h.payloadExecutionBlock(ctx, slot, blockNumberHex)
+	if err != nil {
+		return nil, upstreamError("failed to get execution block", err)
+	}
+
+	// Retrieve the receipts so the reward reflects the gas each transaction actually consumed.
+	receipts, err := h.payloadReceipts(ctx, slot, blockNumberHex)
+	if err != nil {
+		return nil, upstreamError("failed to get block receipts", err)
+	}
+	return newSlotBlock(beaconBlock, execBlock, receipts)
+}
+
+// payloadBlockNumber returns the hexadecimal number of the execution block in a beacon block's execution payload.
+func payloadBlockNumber(beaconBlock *models.BeaconBlockResponse) (string, error) {
+	// Blocks of the phase0 and altair forks carry no execution payload at all, and bellatrix blocks from before the
+	// merge transition carry an empty one with a zero block hash. Neither is a missing payload, whatever the configured merge slot.
+	payload := beaconBlock.Data.Message.Body.ExecutionPayload
+	if beaconBlock.Version == "phase0" || beaconBlock.Version == "altair" || isZeroHash(payload.BlockHash) {
+		return "", errPreMergeSlot
+	}
+
 	// Extract the block number from the beacon block's execution payload.
-	blockNumberDecimal := beaconBlock.Data.Message.Body.ExecutionPayload.BlockNumber
+	blockNumberDecimal := payload.BlockNumber
 	if blockNumberDecimal == "" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "no execution payload for this slot"})
-		return
+		return "", &requestError{http.StatusNotFound, utils.CodeNotFound, "no execution payload for this slot", nil}
 	}
 
 	// Convert the block number to hexadecimal format.
 	blockNumberInt, err := strconv.ParseUint(blockNumberDecimal, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid block number format"})
-		return
+		return "", internalError("invalid block number format", err)
 	}
-	blockNumberHex := fmt.Sprintf("0x%x", blockNumberInt)
+	return fmt.Sprintf("0x%x", blockNumberInt), nil
+}
 
-	// Retrieve the execution block using the block number in hexadecimal format.
-	execBlock, err := h.executionService.GetExecutionBlockByNumber(blockNumberHex)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get execution block"})
-		return
+// isZeroHash reports whether a 0x-prefixed hash consists of zero bytes only, as the block hash of an empty execution payload does.
+func isZeroHash(hash string) bool {
+	digits := strings.TrimPrefix(hash, "0x")
+	return digits != "" && strings.Trim(digits, "0") == ""
+}
+
+// newSlotBlock checks that the receipts match the execution block and bundles them with the beacon block.
+func newSlotBlock(beaconBlock *models.BeaconBlockResponse, execBlock *models.ExecutionBlockFullResponse, receipts []models.TransactionReceipt) (*slotBlock, error) {
+	if len(receipts) != len(execBlock.Result.Transactions) {
+		return nil, internalError("block receipts do not match block transactions", fmt.Errorf("got %d receipts for %d transactions", len(receipts), len(execBlock.Result.Transactions)))
+	}
+	// Blocks fetched with transaction hashes only take what the reward math needs from the receipts.
+	if hashOnlyTransactions(execBlock) {
+		execBlock = receiptTransactions(execBlock, receipts)
 	}
 
-	// Calculate the total reward by iterating over each transaction in the execution block.
 	baseFee, err := hexToBigInt(execBlock.Result.BaseFeePerGas)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid base fee"})
-		return
+		return nil, internalError("invalid base fee", err)
 	}
 
-	totalReward := big.NewInt(0)
-	for _, tx := range execBlock.Result.Transactions {
-		gasPrice, err := hexToBigInt(tx.GasPrice)
+	return &slotBlock{
+		beacon:   beaconBlock,
+		exec:     execBlock,
+		receipts: receipts,
+		baseFee:  baseFee,
+	}, nil
+}
+
+// transactionRewards returns the priority fee paid to the proposer by every transaction of the block, attributed in the given mode.
+// Transactions whose fee or gas fields cannot be parsed are skipped.
+func (b *slotBlock) transactionRewards(mode rewardMode) []models.TransactionReward {
+	rewards := make([]models.TransactionReward, 0, len(b.exec.Result.Transactions))
+	for i, tx := range b.exec.Result.Transactions {
+		priorityFee, err := b.priorityFee(i, mode)
 		if err != nil {
 			continue
 		}
-		gas, err := hexToBigInt(tx.Gas)
+		gasUsed, err := hexToBigInt(b.receipts[i].GasUsed)
 		if err != nil {
 			continue
 		}
 
-		// Calculate the transaction reward if the gas price is greater than the base fee.
-		if gasPrice.Cmp(baseFee) > 0 {
-			priorityFee := big.NewInt(0).Sub(gasPrice, baseFee)
-			txReward := big.NewInt(0).Mul(priorityFee, gas)
-			totalReward.Add(totalReward, txReward)
+		// The proposer earns the priority fee for every unit of gas the transaction consumed.
+		// Blob gas is accounted separately from gasUsed and its fee is burnt in full, so it never adds to the reward.
+		reward := big.NewInt(0).Mul(priorityFee, gasUsed)
+		rewards = append(rewards, models.TransactionReward{
+			Hash:              tx.Hash,
+			PriorityFeePerGas: priorityFee.String(),
+			GasUsed:           gasUsed.String(),
+			Reward:            formatUnits(reward, "gwei"),
+			RewardWei:         reward.String(),
+		})
+	}
+	return rewards
+}
+
+// transactionTypeCounts buckets the block's transactions by their type.
+// Transactions of unknown or unparseable types are counted as other.
+func (b *slotBlock) transactionTypeCounts() *models.TransactionTypeCounts {
+	counts := &models.TransactionTypeCounts{}
+	for _, tx := range b.exec.Result.Transactions {
+		txType := tx.Type
+		if txType == "" {
+			txType = "0x0" // Nodes may omit the type of legacy transactions.
+		}
+		switch n, err := strconv.ParseUint(strings.TrimPrefix(txType, "0x"), 16, 8); {
+		case err != nil:
+			counts.Other++
+		case n == 0:
+			counts.Legacy++
+		case n == 1:
+			counts.AccessList++
+		case n == 2:
+			counts.DynamicFee++
+		case n == 3:
+			counts.Blob++
+		default:
+			counts.Other++
+		}
+	}
+	return counts
+}
+
+// blobFeesBurnt returns the blob gas used by the block and the blob base fees it burnt, as blob gas used * blob gas price.
+// Both are nil for blocks from before Dencun, which cannot carry blobs.
+func (b *slotBlock) blobFeesBurnt() (*big.Int, *big.Int, error) {
+	if b.exec.Result.BlobGasUsed == "" {
+		return nil, nil, nil
+	}
+	blobGasUsed, err := hexToBigInt(b.exec.Result.BlobGasUsed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid blob gas used %q: %w", b.exec.Result.BlobGasUsed, err)
+	}
+
+	burnt := big.NewInt(0)
+	for _, receipt := range b.receipts {
+		if receipt.BlobGasUsed == "" {
+			continue // Only blob transactions consume blob gas.
+		}
+		gasUsed, err := hexToBigInt(receipt.BlobGasUsed)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid blob gas used %q of transaction %s: %w", receipt.BlobGasUsed, receipt.TransactionHash, err)
+		}
+		gasPrice, err := hexToBigInt(receipt.BlobGasPrice)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid blob gas price %q of transaction %s: %w", receipt.BlobGasPrice, receipt.TransactionHash, err)
+		}
+		burnt.Add(burnt, big.NewInt(0).Mul(gasUsed, gasPrice))
+	}
+	return blobGasUsed, burnt, nil
+}
+
+// mevPayment returns the change of the fee recipient's balance across the block that is not explained by the priority fees
+// or by withdrawals credited to it. A positive payment is typically a builder paying the proposer in the block's last transaction;
+// a negative one means the fee recipient is a builder that paid out more than it earned.
+func (h *BlockRewardHandler) mevPayment(ctx context.Context, block *slotBlock, priorityFees *big.Int) (*big.Int, error) {
+	payload := block.beacon.Data.Message.Body.ExecutionPayload
+	blockNumber, err := strconv.ParseUint(payload.BlockNumber, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	delta, err := h.executionService.GetBalanceDelta(ctx, payload.FeeRecipient, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := delta.Sub(delta, priorityFees)
+	for _, withdrawal := range payload.Withdrawals {
+		if !strings.EqualFold(withdrawal.Address, payload.FeeRecipient) {
+			continue
 		}
+		amount, ok := new(big.Int).SetString(withdrawal.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %q of withdrawal %s", withdrawal.Amount, withdrawal.Index)
+		}
+		// Withdrawal amounts are denominated in gwei.
+		payment.Sub(payment, amount.Mul(amount, big.NewInt(1_000_000_000)))
+	}
+	return payment, nil
+}
+
+// computeBlockReward computes the proposer reward for a slot from the consensus and execution layers.
+// Failures are returned as *requestError values describing the response to send to the client.
+func (h *BlockRewardHandler) computeBlockReward(ctx context.Context, slot uint64, mode rewardMode) (*models.BlockReward, error) {
+	block, err := h.fetchSlotBlock(ctx, slot)
+	if err != nil {
+		return nil, err
 	}
+	return h.rewardForBlock(ctx, slot, block, mode)
+}
 
-	// Convert the total reward from wei to gwei.
-	divider := big.NewInt(1_000_000_000)
-	rewardInGwei := big.NewInt(0).Div(totalReward, divider)
+// rewardForBlock computes the proposer reward of a block retrieved for the given slot.
+// Failures are returned as *requestError values describing the response to send to the client.
+func (h *BlockRewardHandler) rewardForBlock(ctx context.Context, slot uint64, block *slotBlock, mode rewardMode) (*models.BlockReward, error) {
+	block, err := h.blockForMode(ctx, block, mode)
+	if err != nil {
+		return nil, err
+	}
 
-	// Determine the status based on the length of the extra data in the execution block.
+	// Calculate the total reward by summing the contribution of each transaction in the execution block.
+	totalReward, err := sumTransactionRewards(block.transactionRewards(mode))
+	if err != nil {
+		return nil, internalError("invalid transaction reward", err)
+	}
+
+	// Calculate the fees burnt by EIP-1559 as the base fee times the gas used by the block.
+	payload := block.beacon.Data.Message.Body.ExecutionPayload
+	payloadBaseFee, ok := new(big.Int).SetString(payload.BaseFeePerGas, 10)
+	if !ok {
+		return nil, internalError("invalid base fee", fmt.Errorf("invalid base fee per gas %q", payload.BaseFeePerGas))
+	}
+	payloadGasUsed, ok := new(big.Int).SetString(payload.GasUsed, 10)
+	if !ok {
+		return nil, internalError("invalid gas used", fmt.Errorf("invalid gas used %q", payload.GasUsed))
+	}
+	burntFees := big.NewInt(0).Mul(payloadBaseFee, payloadGasUsed)
+
+	// Calculate how much of its gas limit the block used.
+	gasUsed, err := hexToBigInt(block.exec.Result.GasUsed)
+	if err != nil {
+		return nil, internalError("invalid gas used", err)
+	}
+	gasLimit, err := hexToBigInt(block.exec.Result.GasLimit)
+	if err != nil || gasLimit.Sign() == 0 {
+		return nil, internalError("invalid gas limit", fmt.Errorf("invalid gas limit %q", block.exec.Result.GasLimit))
+	}
+	// Scale by 10^4 before dividing to keep two decimals of the percentage.
+	utilization := big.NewInt(0).Div(big.NewInt(0).Mul(gasUsed, big.NewInt(10000)), gasLimit)
+
+	// Calculate the blob base fees burnt by the block's blob transactions, which are reported separately.
+	blobGasUsed, blobFeesBurnt, err := block.blobFeesBurnt()
+	if err != nil {
+		return nil, internalError("invalid blob fees", err)
+	}
+
+	// Measure payments to the fee recipient beyond the priority fees, which reveal builder payments.
+	// Balances of old blocks require an archive node, so the payment is omitted rather than failing the request.
+	// An empty block carries no transaction that could pay the fee recipient, so its payment is zero without asking for balances.
+	// A failed lookup leaves the reward partial, so that a later request can try again.
+	partial := false
+	var mevPayment *big.Int
+	if len(block.exec.Result.Transactions) == 0 {
+		mevPayment = big.NewInt(0)
+	} else if mevPayment, err = h.mevPayment(ctx, block, totalReward); err != nil {
+		slog.DebugContext(ctx, "failed to measure MEV payment", "slot", slot, "error", err)
+		partial = true
+	}
+
+	// Ask the relays, when configured, which payload they delivered for the slot.
+	delivered, err := h.deliveredPayload(ctx, slot, payload.BlockHash)
+	if err != nil {
+		slog.DebugContext(ctx, "failed to get delivered payload from relays", "slot", slot, "error", err)
+		partial = true
+	}
+
+	// Determine the status by matching the extra data against the signatures of known builders and relays.
+	// A block without a recognized signature is still built by a builder if a relay delivered it
+	// or its fee recipient's balance moved by more than the fees.
+	builder := detectBuilder(block.exec.Result.ExtraData)
 	status := "vanilla"
-	if len(execBlock.Result.ExtraData) > 20 {
+	if builder != "" || delivered != nil || (mevPayment != nil && mevPayment.Sign() != 0) {
 		status = "relay"
 	}
 
-	// Respond with the calculated reward and status.
-	c.JSON(http.StatusOK, gin.H{
-		"status": status,
-		"reward": rewardInGwei.String(),
-	})
+	txCount := len(block.exec.Result.Transactions)
+	reward := &models.BlockReward{
+		Status:         status,
+		Builder:        builder,
+		Reward:         formatUnits(totalReward, "gwei"),
+		Unit:           "gwei",
+		RewardWei:      totalReward.String(),
+		Mode:           string(mode),
+		BurntFees:      formatUnits(burntFees, "gwei"),
+		BurntFeesWei:   burntFees.String(),
+		TxCount:        &txCount,
+		TxTypes:        block.transactionTypeCounts(),
+		GasUsed:        gasUsed.String(),
+		GasLimit:       gasLimit.String(),
+		GasUtilization: formatDecimal(utilization, 2),
+		BlockNumber:    payload.BlockNumber,
+		BlockHash:      block.exec.Result.Hash,
+		ParentHash:     block.exec.Result.ParentHash,
+		FeeRecipient:   payload.FeeRecipient,
+		ProposerIndex:  block.beacon.Data.Message.ProposerIndex,
+		Timestamp:      h.consensusService.SlotToTime(slot).UTC().Format(time.RFC3339),
+		Partial:        partial,
+		StateRoot:      block.beacon.Data.Message.StateRoot,
+	}
+	reward.PriorityFeeTotal = formatUnits(totalReward, "gwei")
+
+	// Reconcile the block's value from the measured payment to the fee recipient; relay data below takes precedence.
+	if mevPayment != nil {
+		reward.MEVPayment = formatUnits(mevPayment, "gwei")
+		reward.MEVPaymentWei = mevPayment.String()
+
+		blockValue := new(big.Int).Add(totalReward, mevPayment)
+		reward.BlockValueTotal = formatUnits(blockValue, "gwei")
+		reward.BlockValueSource = "priority_fees"
+		if mevPayment.Sign() != 0 {
+			reward.BlockValueSource = "builder_payment"
+		}
+	}
+	if delivered != nil {
+		reward.Relays = delivered.Relays
+		reward.BuilderPubkey = delivered.BuilderPubkey
+		if value, ok := new(big.Int).SetString(delivered.Value, 10); ok {
+			reward.MEVValue = formatUnits(value, "gwei")
+			reward.MEVValueWei = value.String()
+
+			// The builder's bid is the value the proposer was promised for the block.
+			reward.BlockValueTotal = reward.MEVValue
+			reward.BlockValueSource = "builder_payment"
+
+			// Reconcile the reported value with what the block actually paid the proposer.
+			paid, err := block.proposerPayment(delivered.ProposerFeeRecipient, totalReward, mevPayment)
+			if errors.Is(err, errHashOnlyTransactions) {
+				// The builder's payments are read from the values of its transactions, which receipts lack.
+				var full *slotBlock
+				if full, err = h.withFullTransactions(ctx, block); err == nil {
+					paid, err = full.proposerPayment(delivered.ProposerFeeRecipient, totalReward, mevPayment)
+				}
+			}
+			if err != nil {
+				slog.DebugContext(ctx, "failed to measure the payment to the proposer", "slot", slot, "error", err)
+				reward.Partial = true
+			} else if paid != nil {
+				reward.MEVValuePaidWei = paid.String()
+				reward.MEVValueMismatch = paid.Cmp(value) != 0
+			}
+		}
+	}
+	if payload.Withdrawals != nil {
+		totalWithdrawals := big.NewInt(0)
+		for _, withdrawal := range payload.Withdrawals {
+			amount, ok := new(big.Int).SetString(withdrawal.Amount, 10)
+			if !ok {
+				return nil, internalError("invalid withdrawal amount", fmt.Errorf("invalid amount %q of withdrawal %s", withdrawal.Amount, withdrawal.Index))
+			}
+			totalWithdrawals.Add(totalWithdrawals, amount)
+		}
+		reward.Withdrawals = payload.Withdrawals
+		reward.TotalWithdrawals = totalWithdrawals.String()
+	}
+	if blobGasUsed != nil {
+		reward.BlobGasUsed = blobGasUsed.String()
+		reward.BlobFeesBurnt = formatUnits(blobFeesBurnt, "gwei")
+		reward.BlobFeesBurntWei = blobFeesBurnt.String()
+	}
+	return reward, nil
+}
+
+// proposerPayment returns what a relay-delivered block paid the proposer's fee recipient, in wei.
+// When the proposer is the block's fee recipient, it receives the priority fees and any payment beyond them, so nil is
+// returned if the latter could not be measured. Otherwise the builder is the fee recipient and pays the proposer with
+// transactions of its own, whose values are added up.
+func (b *slotBlock) proposerPayment(proposerFeeRecipient string, priorityFees, mevPayment *big.Int) (*big.Int, error) {
+	feeRecipient := b.beacon.Data.Message.Body.ExecutionPayload.FeeRecipient
+	if strings.EqualFold(feeRecipient, proposerFeeRecipient) {
+		if mevPayment == nil {
+			return nil, nil
+		}
+		return new(big.Int).Add(priorityFees, mevPayment), nil
+	}
+
+	if b.hashOnly() {
+		return nil, errHashOnlyTransactions
+	}
+	paid := big.NewInt(0)
+	for _, tx := range b.exec.Result.Transactions {
+		if !strings.EqualFold(tx.From, feeRecipient) || !strings.EqualFold(tx.To, proposerFeeRecipient) {
+			continue
+		}
+		value, err := hexToBigInt(tx.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q of transaction %s: %w", tx.Value, tx.Hash, err)
+		}
+		paid.Add(paid, value)
+	}
+	return paid, nil
+}
+
+// deliveredPayload returns the payload the relays delivered for a slot, if relay data is configured and the delivered
+// block is the one included in the slot. Relay failures only leave the relay fields out, so the caller logs them
+// rather than failing the request; a slot no relay delivered a payload for is not a failure.
+func (h *BlockRewardHandler) deliveredPayload(ctx context.Context, slot uint64, blockHash string) (*services.DeliveredPayload, error) {
+	if h.relayService == nil {
+		return nil, nil
+	}
+	delivered, err := h.relayService.GetDeliveredPayload(ctx, slot)
+	if errors.Is(err, services.ErrPayloadNotDelivered) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	// A relay may report a payload the proposer never published, e.g. when the proposer equivocated or missed the slot.
+	if !strings.EqualFold(delivered.BlockHash, blockHash) {
+		return nil, nil
+	}
+	return delivered, nil
+}
+
+// sumTransactionRewards adds up the wei rewards of the given transactions.
+func sumTransactionRewards(rewards []models.TransactionReward) (*big.Int, error) {
+	total := big.NewInt(0)
+	for _, reward := range rewards {
+		wei, ok := new(big.Int).SetString(reward.RewardWei, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid reward %q of transaction %s", reward.RewardWei, reward.Hash)
+		}
+		total.Add(total, wei)
+	}
+	return total, nil
 }
 
 // GetSyncDuties handles HTTP requests to retrieve sync committee duties for a given slot.
 func (h *BlockRewardHandler) GetSyncDuties(c *gin.Context) {
 	// Parse the slot parameter from the request URL.
-	slotParam := c.Param("slot")
-	slot, err := strconv.ParseUint(slotParam, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid slot parameter"})
+	slot, ok := h.resolveSlotParam(c)
+	if !ok {
 		return
 	}
 
-	// Ensure the requested slot is not too far in the future by comparing it with the current head slot.
-	headSlot, err := h.consensusService.GetHeadSlot()
+	// Ensure the requested slot is not too far in the future. Sync committees are chosen one period in advance,
+	// so slots up to the end of the period after the head's are answerable.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch head slot"})
+		respondError(c, upstreamError("failed to fetch head slot", err))
 		return
 	}
-	if slot > headSlot {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "requested slot is too far in the future"})
+	if h.consensusService.SyncCommitteePeriod(slot) > h.consensusService.SyncCommitteePeriod(headSlot)+1 {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is too far in the future")
 		return
 	}
 
 	// Retrieve the sync committee duties for the specified slot.
-	validators, err := h.consensusService.GetSyncCommitteeDuties(slot)
+	validators, err := h.consensusService.GetSyncCommitteeDuties(c.Request.Context(), slot)
 	if err != nil {
-		if err.Error() == "sync committee duties not found for this slot" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "sync committee duties not found"})
+		if errors.Is(err, services.ErrSyncCommitteeNotFound) {
+			utils.RespondError(c, http.StatusNotFound, utils.CodeNotFound, "sync committee duties not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get sync committee duties"})
+		respondError(c, upstreamError("failed to get sync committee duties", err))
+		return
+	}
+
+	// Select the requested page of the committee, which defaults to all of it.
+	total := len(validators)
+	offset, end, ok := parsePageParams(c, total)
+	if !ok {
+		return
+	}
+
+	// Resolve the validator indices of the page to public keys when requested.
+	members, ok := h.syncCommitteeMembers(c, validators[offset:end])
+	if !ok {
 		return
 	}
 
-	// Respond with the list of validators in the sync committee.
+	// Respond with the page of validators in the sync committee and the size of the whole committee,
+	// along with the slot's place within its epoch.
+	slotsPerEpoch := h.consensusService.SlotsPerEpoch()
 	c.JSON(http.StatusOK, gin.H{
-		"validators": validators,
+		"validators":    members,
+		"total":         total,
+		"epoch":         slot / slotsPerEpoch,
+		"slot_in_epoch": slot % slotsPerEpoch,
 	})
 }
 
-// hexToBigInt converts a hexadecimal string to a big.Int.
-func hexToBigInt(hexStr string) (*big.Int, error) {
-	if len(hexStr) > 2 && hexStr[:2] == "0x" {
-		i := new(big.Int)
-		_, ok := i.SetString(hexStr[2:], 16)
-		if !ok {
-			return nil, fmt.Errorf("failed to parse hex string")
+// priorityFee returns the priority fee per gas that the i-th transaction of the block paid to the proposer.
+// In effective mode it is derived from the receipt's effective gas price, falling back to the signed fee fields
+// for receipts that do not report it.
+func (b *slotBlock) priorityFee(i int, mode rewardMode) (*big.Int, error) {
+	if mode == modeEffective && b.receipts[i].EffectiveGasPrice != "" {
+		gasPrice, err := hexToBigInt(b.receipts[i].EffectiveGasPrice)
+		if err != nil {
+			return nil, err
+		}
+		priorityFee := gasPrice.Sub(gasPrice, b.baseFee)
+		if priorityFee.Sign() < 0 {
+			return big.NewInt(0), nil // A transaction charged below the base fee pays the proposer nothing.
 		}
-		return i, nil
+		return priorityFee, nil
 	}
-	return nil, fmt.Errorf("invalid hex format")
+	return effectivePriorityFee(b.exec.Result.Transactions[i], b.baseFee)
+}
+
+// effectivePriorityFee returns the priority fee per gas that the block proposer receives for a transaction.
+// Dynamic fee (EIP-1559) transactions pay min(maxPriorityFeePerGas, maxFeePerGas - baseFee),
+// while legacy and access list transactions pay gasPrice - baseFee. The result is never negative.
+func effectivePriorityFee(tx models.ExecutionBlockTx, baseFee *big.Int) (*big.Int, error) {
+	var priorityFee *big.Int
+	if tx.MaxFeePerGas != "" && tx.MaxPriorityFeePerGas != "" {
+		maxFee, err := hexToBigInt(tx.MaxFeePerGas)
+		if err != nil {
+			return nil, err
+		}
+		maxPriorityFee, err := hexToBigInt(tx.MaxPriorityFeePerGas)
+		if err != nil {
+			return nil, err
+		}
+		priorityFee = big.NewInt(0).Sub(maxFee, baseFee)
+		if maxPriorityFee.Cmp(priorityFee) < 0 {
+			priorityFee = maxPriorityFee
+		}
+	} else {
+		gasPrice, err := hexToBigInt(tx.GasPrice)
+		if err != nil {
+			return nil, err
+		}
+		priorityFee = big.NewInt(0).Sub(gasPrice, baseFee)
+	}
+
+	// A transaction included below the base fee pays the proposer nothing.
+	if priorityFee.Sign() < 0 {
+		return big.NewInt(0), nil
+	}
+	return priorityFee, nil
+}
+
+// hexToBigInt converts a 0x-prefixed hexadecimal quantity to a big.Int.
+func hexToBigInt(hexStr string) (*big.Int, error) {
+	return utils.ParseHexQuantity(hexStr)
 }
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+)
+
+func TestRewardForEmptyBlock(t *testing.T) {
+	// An empty block needs no balances, so any request to the execution client fails the test.
+	execution := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected execution client request")
+		http.Error(w, "unexpected request", http.StatusInternalServerError)
+	}))
+	defer execution.Close()
+	h := NewBlockRewardHandler(services.NewConsensusService("http://127.0.0.1:0"), services.NewExecutionService(execution.URL))
+
+	var beaconBlock models.BeaconBlockResponse
+	if err := json.Unmarshal([]byte(`{"version":"deneb","data":{"message":{"body":{"execution_payload":{
+		"block_number":"20000002","fee_recipient":"0xcccccccccccccccccccccccccccccccccccccccc","extra_data":"0x",
+		"base_fee_per_gas":"7000000000","gas_used":"0"}}}}}`), &beaconBlock); err != nil {
+		t.Fatal(err)
+	}
+	var execBlock models.ExecutionBlockFullResponse
+	if err := json.Unmarshal([]byte(`{"result":{"number":"0x1312d02","baseFeePerGas":"0x1a13b8600","gasUsed":"0x0",
+		"gasLimit":"0x1c9c380","extraData":"0x","transactions":[]}}`), &execBlock); err != nil {
+		t.Fatal(err)
+	}
+	block, err := newSlotBlock(&beaconBlock, &execBlock, []models.TransactionReceipt{})
+	if err != nil {
+		t.Fatalf("newSlotBlock: %v", err)
+	}
+
+	reward, err := h.rewardForBlock(context.Background(), 9_000_003, block, modeEffective)
+	if err != nil {
+		t.Fatalf("rewardForBlock: %v", err)
+	}
+	if reward.Status != "vanilla" || reward.Reward != "0" || reward.RewardWei != "0" || reward.BurntFees != "0" || reward.BurntFeesWei != "0" {
+		t.Errorf("got status %q, reward %q (%q wei) and burnt fees %q (%q wei), want vanilla and zeros",
+			reward.Status, reward.Reward, reward.RewardWei, reward.BurntFees, reward.BurntFeesWei)
+	}
+	if reward.TxCount == nil || *reward.TxCount != 0 {
+		t.Errorf("tx_count = %v, want 0", reward.TxCount)
+	}
+	if reward.GasUtilization != "0" || reward.MEVPayment != "0" || reward.MEVPaymentWei != "0" {
+		t.Errorf("got gas utilization %q and MEV payment %q (%q wei), want zeros", reward.GasUtilization, reward.MEVPayment, reward.MEVPaymentWei)
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// knownBuilders maps a signature found in a block's extra data to the builder or relay that produced the block.
+// Signatures are matched case-insensitively against the decoded extra data, in order, so more specific
+// signatures must come before more general ones. New builders only need a new entry here.
+var knownBuilders = []struct {
+	signature string
+	name      string
+}{
+	{"Illuminate Dmocratize Dstribute", "Flashbots"},
+	{"Illuminate Dmocrtz Dstrib Prtct", "Flashbots"},
+	{"Flashbots", "Flashbots"},
+	{"bloXroute", "bloXroute"},
+	{"builder0x69", "builder0x69"},
+	{"beaverbuild", "beaverbuild"},
+	{"rsync-builder", "rsync-builder"},
+	{"titanbuilder", "Titan"},
+	{"BuilderNet", "BuilderNet"},
+	{"btcs.com/builder", "BTCS"},
+}
+
+// decodeExtraData decodes the hex-encoded extra data of an execution block into a UTF-8 string.
+// Bytes that do not form valid UTF-8 are dropped.
+func decodeExtraData(extraData string) string {
+	b, err := hex.DecodeString(strings.TrimPrefix(extraData, "0x"))
+	if err != nil {
+		return ""
+	}
+	return strings.ToValidUTF8(string(b), "")
+}
+
+// detectBuilder returns the name of the builder or relay whose signature appears in the block's extra data,
+// or an empty string when the extra data does not match any known signature.
+func detectBuilder(extraData string) string {
+	decoded := strings.ToLower(decodeExtraData(extraData))
+	for _, builder := range knownBuilders {
+		if strings.Contains(decoded, strings.ToLower(builder.signature)) {
+			return builder.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBlockRewardByBlockNumber handles HTTP requests to retrieve the block reward for an execution block number.
+// The number may be decimal or 0x-prefixed hexadecimal. The slot of the block is derived from its timestamp,
+// and the reward is computed exactly as for GetBlockReward.
+func (h *BlockRewardHandler) GetBlockRewardByBlockNumber(c *gin.Context) {
+	// Parse the block number parameter from the request URL.
+	number, err := utils.ParseUint(c.Param("number"))
+	if err != nil {
+		respondInvalidInput(c, "number", err)
+		return
+	}
+
+	// Parse the unit the reward should be expressed in, defaulting to gwei.
+	unit, ok := parseUnitParam(c)
+	if !ok {
+		return
+	}
+
+	// Parse how the transaction rewards are attributed, defaulting to the effective gas price.
+	mode, ok := parseModeParam(c)
+	if !ok {
+		return
+	}
+
+	// Retrieve the execution block to learn when, and therefore in which slot, it was proposed.
+	execBlock, err := h.executionService.GetExecutionBlockByNumber(c.Request.Context(), fmt.Sprintf("0x%x", number))
+	if err != nil {
+		if errors.Is(err, services.ErrExecutionBlockNotFound) {
+			utils.RespondError(c, http.StatusNotFound, utils.CodeNotFound, "execution block not found")
+			return
+		}
+		respondError(c, upstreamError("failed to get execution block", err))
+		return
+	}
+	timestamp, err := hexToBigInt(execBlock.Result.Timestamp)
+	if err != nil || !timestamp.IsInt64() {
+		respondError(c, internalError("invalid block timestamp", fmt.Errorf("invalid timestamp %q", execBlock.Result.Timestamp)))
+		return
+	}
+	slot, err := h.consensusService.TimeToSlot(time.Unix(timestamp.Int64(), 0))
+	if err != nil {
+		// Blocks from before the beacon chain genesis are pre-merge by definition, but have no slot.
+		c.JSON(http.StatusOK, models.PreMergeBlockReward{Status: "pre_merge", Code: utils.CodePreMergeSlot})
+		return
+	}
+
+	reward, err := h.blockRewardInMode(c.Request.Context(), slot, mode)
+	if errors.Is(err, errPreMergeSlot) {
+		h.respondSlotData(c, slot, true, preMergeResult(slot))
+		return
+	} else if err != nil {
+		respondError(c, err)
+		return
+	}
+	if reward.BlockNumber != strconv.FormatUint(number, 10) {
+		respondError(c, internalError("execution block does not match its slot", fmt.Errorf("slot %d holds block %s, not %d", slot, reward.BlockNumber, number)))
+		return
+	}
+
+	// Express the reward in the requested unit on a copy, since the computed reward may be shared through the cache.
+	resp, err := rewardInUnit(reward, unit)
+	if err != nil {
+		respondError(c, internalError("invalid reward", err))
+		return
+	}
+
+	// Respond with the slot of the block alongside its reward, cacheable for good once the slot is finalized unless the reward is partial.
+	h.respondSlotData(c, slot, !reward.Partial, models.SlotRewardResult{Slot: slot, BlockReward: resp})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBlockRewardByRoot handles HTTP requests to retrieve the block reward for a beacon block root.
+// Unlike a slot, a root identifies one block unambiguously, including blocks that were reorged out of the canonical chain.
+// Such rewards are computed from the requested block itself and are never cached.
+func (h *BlockRewardHandler) GetBlockRewardByRoot(c *gin.Context) {
+	// Parse and validate the block root parameter from the request URL.
+	root, err := utils.ParseHexData(c.Param("root"), 32)
+	if err != nil {
+		respondInvalidInput(c, "root", err)
+		return
+	}
+
+	// Parse the unit the reward should be expressed in, defaulting to gwei.
+	unit, ok := parseUnitParam(c)
+	if !ok {
+		return
+	}
+
+	// Parse how the transaction rewards are attributed, defaulting to the effective gas price.
+	mode, ok := parseModeParam(c)
+	if !ok {
+		return
+	}
+
+	// Retrieve the beacon block by its root.
+	beaconBlock, err := h.consensusService.GetBeaconBlock(c.Request.Context(), root)
+	if err != nil {
+		if errors.Is(err, services.ErrBlockNotFound) {
+			utils.RespondError(c, http.StatusNotFound, utils.CodeNotFound, "block not found")
+			return
+		}
+		respondError(c, upstreamError("failed to get beacon block", err))
+		return
+	}
+	slot, err := strconv.ParseUint(beaconBlock.Data.Message.Slot, 10, 64)
+	if err != nil {
+		respondError(c, internalError("invalid block slot", err))
+		return
+	}
+
+	// Compute the reward from the requested block rather than from whichever block is canonical at its slot.
+	// Blocks before the merge earn no execution-layer reward.
+	var block *slotBlock
+	if h.consensusService.Network().IsPreMerge(slot) {
+		err = errPreMergeSlot
+	} else {
+		block, err = h.completeSlotBlock(c.Request.Context(), slot, beaconBlock)
+	}
+	if errors.Is(err, errPreMergeSlot) {
+		h.respondSlotData(c, slot, true, preMergeResult(slot))
+		return
+	} else if err != nil {
+		respondError(c, err)
+		return
+	}
+	reward, err := h.rewardForBlock(c.Request.Context(), slot, block, mode)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := rewardInUnit(reward, unit)
+	if err != nil {
+		respondError(c, internalError("invalid reward", err))
+		return
+	}
+
+	// Respond with the slot of the block alongside its reward, cacheable for good once the slot is finalized unless the reward is partial.
+	h.respondSlotData(c, slot, !reward.Partial, models.SlotRewardResult{Slot: slot, BlockReward: resp})
+}
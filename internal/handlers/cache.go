@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"eth-rewards-api/internal/cache"
+	"eth-rewards-api/internal/models"
+)
+
+// finalizedRefreshInterval limits how often the finalized slot is fetched from the consensus layer.
+// Finality only advances once per epoch, so refreshing once per slot is more than enough.
+const finalizedRefreshInterval = 12 * time.Second
+
+// headCheckInterval limits how often the head is compared with the previous one to detect reorgs.
+// Rewards of non-finalized slots may be served for up to this long, plus the time a check takes, after a reorg replaced their block.
+const headCheckInterval = 2 * time.Second
+
+// maxUnfinalizedRewards bounds the number of non-finalized slots cached. Finality normally trails the head
+// by two to three epochs, so the bound only matters while the chain is not finalizing.
+const maxUnfinalizedRewards = 256
+
+// rewardCache stores computed rewards. Rewards of finalized slots can never change and are kept in an LRU.
+// Rewards of more recent slots are kept separately and dropped as soon as a reorg is detected, since it may have
+// replaced their blocks; they move to the LRU once their slot is finalized and their block is still the canonical one.
+// A reward computed before a reorg was detected is not stored, since it may describe a block the reorg replaced.
+type rewardCache struct {
+	lru *cache.LRU[uint64, *models.BlockReward]
+
+	mu            sync.Mutex
+	finalizedSlot uint64
+	checkedAt     time.Time
+
+	unfinalized   map[uint64]*models.BlockReward // The rewards of slots beyond finalizedSlot.
+	headSlot      uint64                         // The slot of the head seen by the last check.
+	headRoot      string                         // The block root of that head; empty before the first check.
+	headCheckedAt time.Time
+	reorgs        uint64 // The number of times the non-finalized rewards were dropped.
+}
+
+// newRewardCache initializes a reward cache holding at most size finalized slots.
+func newRewardCache(size int) *rewardCache {
+	return &rewardCache{
+		lru:         cache.NewLRU[uint64, *models.BlockReward](size),
+		unfinalized: make(map[uint64]*models.BlockReward),
+	}
+}
+
+// get returns the cached reward of a slot. Before serving the reward of a non-finalized slot, the head is checked
+// for a reorg, which drops every non-finalized reward.
+func (rc *rewardCache) get(ctx context.Context, cs ConsensusProvider, slot uint64) (*models.BlockReward, bool) {
+	if reward, ok := rc.lru.Get(slot); ok {
+		return reward, true
+	}
+
+	rc.mu.Lock()
+	_, ok := rc.unfinalized[slot]
+	rc.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	rc.checkHead(ctx, cs)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	reward, ok := rc.unfinalized[slot]
+	return reward, ok
+}
+
+// generation returns a value that changes whenever the non-finalized rewards are dropped. It is taken before a reward
+// is computed and passed to store, which discards the reward if a reorg was detected in between.
+func (rc *rewardCache) generation() uint64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.reorgs
+}
+
+// store caches the reward of a slot: in the LRU when the slot is finalized, and with the non-finalized rewards otherwise.
+// The reward is discarded if the non-finalized rewards were dropped since generation was taken.
+func (rc *rewardCache) store(ctx context.Context, cs ConsensusProvider, slot uint64, reward *models.BlockReward, generation uint64) {
+	if rc.isFinalized(ctx, cs, slot) {
+		if rc.generation() == generation {
+			rc.lru.Add(slot, reward)
+		}
+		return
+	}
+
+	// Check the head here too, so that reorgs are tracked from the first non-finalized reward on, and so that a reorg
+	// that happened while the reward was computed is detected before the reward is stored.
+	rc.checkHead(ctx, cs)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.reorgs != generation {
+		slog.DebugContext(ctx, "discarding reward computed before a reorg", "slot", slot)
+		return
+	}
+	if len(rc.unfinalized) < maxUnfinalizedRewards {
+		rc.unfinalized[slot] = reward
+	}
+}
+
+// isFinalized reports whether the slot is at or below the latest finalized slot.
+// The finalized slot is refreshed from the consensus layer when the slot is beyond the last known value. The refresh
+// is made without holding rc.mu, and callers arriving while it is in flight answer from the last known value.
+func (rc *rewardCache) isFinalized(ctx context.Context, cs ConsensusProvider, slot uint64) bool {
+	rc.mu.Lock()
+	if slot <= rc.finalizedSlot {
+		rc.mu.Unlock()
+		return true
+	}
+	if time.Since(rc.checkedAt) < finalizedRefreshInterval {
+		rc.mu.Unlock()
+		return false
+	}
+	// Claim the refresh, so that concurrent callers do not repeat it while it is in flight.
+	rc.checkedAt = time.Now()
+	rc.mu.Unlock()
+
+	finalizedSlot, err := cs.ResolveSlot(ctx, "finalized")
+
+	rc.mu.Lock()
+	if err != nil {
+		rc.checkedAt = time.Time{} // Let the next call try again.
+		rc.mu.Unlock()
+		return false // Treat the slot as unfinalized when finality cannot be determined.
+	}
+	rc.finalizedSlot = max(rc.finalizedSlot, finalizedSlot)
+	finalized := slot <= rc.finalizedSlot
+
+	// Rewards of slots that became final can no longer change, so they move to the LRU.
+	promoted := make(map[uint64]*models.BlockReward)
+	for cached, reward := range rc.unfinalized {
+		if cached <= rc.finalizedSlot {
+			promoted[cached] = reward
+			delete(rc.unfinalized, cached)
+		}
+	}
+	rc.mu.Unlock()
+
+	rc.promote(ctx, cs, promoted)
+	return finalized
+}
+
+// promote adds the rewards of newly finalized slots to the LRU. A reorg may have replaced the block a reward was
+// computed from without checkHead noticing, so a reward is only kept if its block is the canonical block at its slot.
+// The headers are fetched without holding rc.mu.
+func (rc *rewardCache) promote(ctx context.Context, cs ConsensusProvider, rewards map[uint64]*models.BlockReward) {
+	for slot, reward := range rewards {
+		header, err := cs.GetBlockHeader(ctx, strconv.FormatUint(slot, 10))
+		if err != nil || reward.StateRoot == "" || header.Data.Header.Message.StateRoot != reward.StateRoot {
+			slog.DebugContext(ctx, "dropping reward of a block that is not canonical", "slot", slot, "error", err)
+			continue
+		}
+		rc.lru.Add(slot, reward)
+	}
+}
+
+// checkHead compares the current head with the one seen by the previous check and drops every non-finalized reward
+// when the chain was reorganized in between. The non-finalized rewards are dropped as well when the head cannot be determined.
+// The head is fetched without holding rc.mu, and callers arriving while a check is in flight skip it, so a reward may
+// still be served for the duration of the check that detects its reorg.
+func (rc *rewardCache) checkHead(ctx context.Context, cs ConsensusProvider) {
+	rc.mu.Lock()
+	if time.Since(rc.headCheckedAt) < headCheckInterval {
+		rc.mu.Unlock()
+		return
+	}
+	// Claim the check, so that concurrent callers do not repeat it while it is in flight.
+	rc.headCheckedAt = time.Now()
+	previousSlot, previousRoot := rc.headSlot, rc.headRoot
+	rc.mu.Unlock()
+
+	headSlot, headRoot, reorged, err := headChange(ctx, cs, previousSlot, previousRoot)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if err != nil {
+		slog.WarnContext(ctx, "failed to check head for reorgs, dropping non-finalized rewards", "error", err)
+		rc.dropUnfinalized()
+		rc.headCheckedAt = time.Time{} // Let the next call try again.
+		return
+	}
+	if reorged {
+		slog.InfoContext(ctx, "reorg detected, dropping non-finalized rewards", "previous_head_slot", previousSlot, "head_slot", headSlot)
+		rc.dropUnfinalized()
+	}
+	rc.headSlot = headSlot
+	rc.headRoot = headRoot
+}
+
+// headChange fetches the current head and reports whether the chain was reorganized since the previous head, given by
+// its slot and root; an empty previous root means there is nothing to compare with. A new head whose parent is not the
+// previous head only means a reorg if the previous head is no longer the canonical block at its slot, since several
+// blocks may have arrived meanwhile.
+func headChange(ctx context.Context, cs ConsensusProvider, previousSlot uint64, previousRoot string) (uint64, string, bool, error) {
+	header, err := cs.GetBlockHeader(ctx, "head")
+	if err != nil {
+		return 0, "", false, err
+	}
+	headSlot, err := strconv.ParseUint(header.Data.Header.Message.Slot, 10, 64)
+	if err != nil {
+		return 0, "", false, fmt.Errorf("invalid head slot %q: %w", header.Data.Header.Message.Slot, err)
+	}
+
+	reorged := false
+	if previousRoot != "" && header.Data.Root != previousRoot && header.Data.Header.Message.ParentRoot != previousRoot {
+		previous, err := cs.GetBlockHeader(ctx, strconv.FormatUint(previousSlot, 10))
+		reorged = err != nil || previous.Data.Root != previousRoot
+	}
+	return headSlot, header.Data.Root, reorged, nil
+}
+
+// dropUnfinalized removes the rewards of every non-finalized slot, and makes store discard the rewards computed before.
+// rc.mu must be held.
+func (rc *rewardCache) dropUnfinalized() {
+	clear(rc.unfinalized)
+	rc.reorgs++
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+)
+
+// newBlockingBeacon returns a consensus service whose beacon node holds every request until release is closed and then
+// answers with the header of the given slot, or of the requested slot for headers requested by slot. A value is sent
+// on requested whenever a request arrives.
+func newBlockingBeacon(t *testing.T, slot uint64) (cs *services.ConsensusService, requested <-chan struct{}, release func()) {
+	t.Helper()
+	arrived := make(chan struct{}, 16)
+	released := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		<-released
+		if requested, err := strconv.ParseUint(path.Base(r.URL.Path), 10, 64); err == nil {
+			writeJSON(w, map[string]any{"data": header(requested)})
+			return
+		}
+		writeJSON(w, map[string]any{"data": header(slot)})
+	}))
+	closed := false
+	release = func() {
+		if !closed {
+			closed = true
+			close(released)
+		}
+	}
+	t.Cleanup(server.Close)
+	t.Cleanup(release)
+	return services.NewConsensusService(server.URL, services.WithRetryPolicy(services.NoRetry)), arrived, release
+}
+
+// within fails the test unless fn returns within a second.
+func within(t *testing.T, what string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("%s blocked while an upstream call of the cache was in flight", what)
+	}
+}
+
+func TestRewardCacheFinalizedRefreshDoesNotBlock(t *testing.T) {
+	ctx := context.Background()
+	cs, requested, release := newBlockingBeacon(t, 200)
+	rc := newRewardCache(16)
+	rc.finalizedSlot = 100
+	rc.headRoot, rc.headCheckedAt = "0x01", time.Now() // Skip the head check while serving unfinalized rewards.
+	reward := &models.BlockReward{Status: "vanilla", StateRoot: stateRoot(150)}
+	rc.unfinalized[150] = reward
+
+	refreshed := make(chan bool)
+	go func() {
+		refreshed <- rc.isFinalized(ctx, cs, 200)
+	}()
+	<-requested
+
+	// While the finalized slot is being refreshed, the cache answers from what it knows.
+	within(t, "isFinalized", func() {
+		if !rc.isFinalized(ctx, cs, 50) {
+			t.Error("slot 50 is not finalized, want finalized")
+		}
+		if rc.isFinalized(ctx, cs, 150) {
+			t.Error("slot 150 is finalized before the refresh completed")
+		}
+	})
+	within(t, "get", func() {
+		if got, ok := rc.get(ctx, cs, 150); !ok || got != reward {
+			t.Errorf("get(150) = %v, %t, want the cached reward", got, ok)
+		}
+	})
+
+	release()
+	if !<-refreshed {
+		t.Error("slot 200 is not finalized after the refresh")
+	}
+	// The reward of the slot that became final moved to the LRU.
+	if got, ok := rc.lru.Get(150); !ok || got != reward {
+		t.Errorf("finalized reward of slot 150 not moved to the LRU")
+	}
+}
+
+func TestRewardCacheHeadCheckDoesNotBlock(t *testing.T) {
+	ctx := context.Background()
+	cs, requested, release := newBlockingBeacon(t, 160)
+	rc := newRewardCache(16)
+	rc.finalizedSlot = 100
+	rc.checkedAt = time.Now() // Skip the finality refresh.
+	reward := &models.BlockReward{Status: "vanilla"}
+	rc.unfinalized[150] = reward
+
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		rc.get(ctx, cs, 150)
+	}()
+	<-requested
+
+	// Callers arriving while the head is being checked skip the check rather than wait for it.
+	within(t, "get", func() {
+		if got, ok := rc.get(ctx, cs, 150); !ok || got != reward {
+			t.Errorf("get(150) = %v, %t, want the cached reward", got, ok)
+		}
+	})
+	within(t, "store", func() {
+		rc.store(ctx, cs, 151, reward, rc.generation())
+	})
+
+	release()
+	<-checked
+	if rc.headSlot != 160 {
+		t.Errorf("head slot = %d after the check, want 160", rc.headSlot)
+	}
+}
+
+// reorgBeacon is a beacon node whose headers can be replaced to simulate reorgs.
+type reorgBeacon struct {
+	mu      sync.Mutex
+	headers map[string]map[string]any // The headers keyed by block id: head, finalized or a slot.
+}
+
+// newReorgBeacon returns a beacon node with the given finalized and head slots, and a consensus service using it.
+func newReorgBeacon(t *testing.T, finalized, head uint64) (*reorgBeacon, *services.ConsensusService) {
+	t.Helper()
+	b := &reorgBeacon{headers: map[string]map[string]any{"finalized": header(finalized)}}
+	b.setHead(header(head))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		h, ok := b.headers[path.Base(r.URL.Path)]
+		b.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, map[string]any{"data": h})
+	}))
+	t.Cleanup(server.Close)
+	return b, services.NewConsensusService(server.URL, services.WithRetryPolicy(services.NoRetry))
+}
+
+// setHead makes a header the head and the canonical header at its slot.
+func (b *reorgBeacon) setHead(h map[string]any) {
+	b.setCanonical(h)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.headers["head"] = h
+}
+
+// setCanonical makes a header the canonical header at its slot.
+func (b *reorgBeacon) setCanonical(h map[string]any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.headers[h["header"].(map[string]any)["message"].(map[string]any)["slot"].(string)] = h
+}
+
+// orphan returns the header of a block at slot on another branch, whose parent is not the block before it.
+func orphan(slot uint64) map[string]any {
+	h := header(slot)
+	h["root"] = fmt.Sprintf("0x%064x", slot<<32)
+	message := h["header"].(map[string]any)["message"].(map[string]any)
+	message["parent_root"] = fmt.Sprintf("0x%064x", (slot-2)<<32)
+	message["state_root"] = fmt.Sprintf("0x%063xe", slot)
+	return h
+}
+
+func TestRewardCacheReorgDropsUnfinalized(t *testing.T) {
+	ctx := context.Background()
+	beacon, cs := newReorgBeacon(t, 100, 150)
+	rc := newRewardCache(16)
+
+	reward := &models.BlockReward{Status: "vanilla", StateRoot: stateRoot(150)}
+	rc.store(ctx, cs, 150, reward, rc.generation())
+	if got, ok := rc.get(ctx, cs, 150); !ok || got != reward {
+		t.Fatalf("get(150) = %v, %t, want the stored reward", got, ok)
+	}
+
+	beacon.setHead(orphan(150))
+	rc.headCheckedAt = time.Time{} // Check the head on the next call.
+	if got, ok := rc.get(ctx, cs, 150); ok {
+		t.Errorf("get(150) = %v after a reorg, want a miss", got)
+	}
+	if len(rc.unfinalized) != 0 {
+		t.Errorf("%d non-finalized rewards left after a reorg, want 0", len(rc.unfinalized))
+	}
+}
+
+func TestRewardCacheDiscardsRewardComputedBeforeReorg(t *testing.T) {
+	ctx := context.Background()
+	beacon, cs := newReorgBeacon(t, 100, 150)
+	rc := newRewardCache(16)
+	rc.checkHead(ctx, cs) // Track the head from here on.
+
+	// The reward is computed from the block at slot 150, which a reorg replaces before it is stored.
+	generation := rc.generation()
+	stale := &models.BlockReward{Status: "vanilla", StateRoot: stateRoot(150)}
+	beacon.setHead(orphan(150))
+	rc.headCheckedAt = time.Time{}
+	rc.store(ctx, cs, 150, stale, generation)
+	if got, ok := rc.get(ctx, cs, 150); ok {
+		t.Errorf("get(150) = %v, want the reward computed before the reorg to be discarded", got)
+	}
+
+	// A reward computed after the reorg was detected is stored.
+	current := &models.BlockReward{Status: "relay"}
+	rc.store(ctx, cs, 150, current, rc.generation())
+	if got, ok := rc.get(ctx, cs, 150); !ok || got != current {
+		t.Errorf("get(150) = %v, %t, want the reward computed after the reorg", got, ok)
+	}
+}
+
+func TestRewardCacheFinalizesCanonicalRewardsOnly(t *testing.T) {
+	ctx := context.Background()
+	beacon, cs := newReorgBeacon(t, 100, 152)
+	rc := newRewardCache(16)
+	rc.finalizedSlot = 100
+	rc.headRoot, rc.headCheckedAt = "0x01", time.Now() // Skip the head check.
+
+	// The reward of slot 150 was computed from a block that a reorg replaced unnoticed.
+	beacon.setCanonical(orphan(150))
+	beacon.setCanonical(header(151))
+	canonical := &models.BlockReward{Status: "vanilla", StateRoot: stateRoot(151)}
+	orphaned := &models.BlockReward{Status: "vanilla", StateRoot: stateRoot(150)}
+	rc.unfinalized[150], rc.unfinalized[151] = orphaned, canonical
+
+	beacon.mu.Lock()
+	beacon.headers["finalized"] = header(152)
+	beacon.mu.Unlock()
+	if !rc.isFinalized(ctx, cs, 152) {
+		t.Fatal("slot 152 is not finalized after the refresh")
+	}
+	if got, ok := rc.lru.Get(151); !ok || got != canonical {
+		t.Errorf("reward of the canonical block at slot 151 not finalized")
+	}
+	if got, ok := rc.lru.Get(150); ok {
+		t.Errorf("reward %v of the replaced block at slot 150 finalized", got)
+	}
+	if len(rc.unfinalized) != 0 {
+		t.Errorf("%d non-finalized rewards left, want 0", len(rc.unfinalized))
+	}
+}
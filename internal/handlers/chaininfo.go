@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"eth-rewards-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetChainInfo handles HTTP requests to retrieve the parameters of the network the upstream nodes serve: the chain ID
+// of the execution client and the genesis and timing constants of the beacon node. Clients use it to configure
+// themselves for the network instead of hardcoding its parameters. The services fetch each parameter only once.
+func (h *BlockRewardHandler) GetChainInfo(c *gin.Context) {
+	// Retrieve the chain ID from the execution client.
+	chainID, err := h.executionService.GetChainID(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to get chain ID", err))
+		return
+	}
+
+	// Retrieve the genesis and the configuration constants from the beacon node.
+	genesis, err := h.consensusService.GetGenesis(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to get genesis", err))
+		return
+	}
+	genesisTime, err := strconv.ParseUint(genesis.Data.GenesisTime, 10, 64)
+	if err != nil {
+		respondError(c, internalError("invalid genesis time", err))
+		return
+	}
+	spec, err := h.consensusService.GetSpec(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to get spec", err))
+		return
+	}
+	resp := models.ChainInfo{
+		Network:               h.consensusService.Network().Name,
+		ChainID:               chainID,
+		GenesisTime:           genesisTime,
+		GenesisValidatorsRoot: genesis.Data.GenesisValidatorsRoot,
+	}
+	for name, value := range map[string]*uint64{
+		"SECONDS_PER_SLOT":                 &resp.SecondsPerSlot,
+		"SLOTS_PER_EPOCH":                  &resp.SlotsPerEpoch,
+		"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": &resp.EpochsPerSyncCommitteePeriod,
+	} {
+		if *value, err = spec.Uint(name); err != nil {
+			respondError(c, internalError("invalid spec", err))
+			return
+		}
+	}
+
+	// Respond with the network parameters, which clients may cache for as long as the service runs.
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"eth-rewards-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCheckpoint handles HTTP requests to retrieve the head slot together with the justified and finalized checkpoints.
+// Clients use it to decide whether the reward of a slot is final or may still change through a reorg.
+func (h *BlockRewardHandler) GetCheckpoint(c *gin.Context) {
+	// Retrieve the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+
+	// Retrieve the justified and finalized checkpoints of the head state.
+	checkpoints, err := h.consensusService.GetFinalityCheckpoints(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to get finality checkpoints", err))
+		return
+	}
+	justified, err := h.chainCheckpoint(checkpoints.Data.CurrentJustified)
+	if err != nil {
+		respondError(c, internalError("invalid justified checkpoint", err))
+		return
+	}
+	finalized, err := h.chainCheckpoint(checkpoints.Data.Finalized)
+	if err != nil {
+		respondError(c, internalError("invalid finalized checkpoint", err))
+		return
+	}
+
+	// Respond with the head and the checkpoints.
+	slotsPerEpoch := h.consensusService.SlotsPerEpoch()
+	c.JSON(http.StatusOK, models.CheckpointResponse{
+		Head:      models.ChainCheckpoint{Slot: headSlot, Epoch: headSlot / slotsPerEpoch},
+		Justified: justified,
+		Finalized: finalized,
+	})
+}
+
+// chainCheckpoint converts a finality checkpoint into its epoch, the first slot of that epoch, and its root.
+func (h *BlockRewardHandler) chainCheckpoint(checkpoint models.Checkpoint) (models.ChainCheckpoint, error) {
+	epoch, err := strconv.ParseUint(checkpoint.Epoch, 10, 64)
+	if err != nil {
+		return models.ChainCheckpoint{}, fmt.Errorf("invalid checkpoint epoch %q", checkpoint.Epoch)
+	}
+	return models.ChainCheckpoint{
+		Slot:  epoch * h.consensusService.SlotsPerEpoch(),
+		Epoch: epoch,
+		Root:  checkpoint.Root,
+	}, nil
+}
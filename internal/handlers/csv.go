@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"eth-rewards-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mimeCSV is the media type of CSV responses.
+const mimeCSV = "text/csv"
+
+// nextCursorHeader carries the cursor of the next page of CSV range responses, which have no body field for it.
+const nextCursorHeader = "X-Next-Cursor"
+
+// csvHeader lists the columns of CSV reward responses.
+var csvHeader = []string{"slot", "status", "reward_gwei", "burnt_gwei", "fee_recipient"}
+
+// wantsCSV reports whether the client's Accept header prefers CSV over JSON. JSON is served when the header is absent,
+// names neither or ranks JSON first.
+func wantsCSV(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, mimeCSV) == mimeCSV
+}
+
+// respondRewardsCSV writes per-slot results as CSV: a header row followed by one row per slot, in the order given.
+// Missed, orphaned and pre-merge slots carry their status with empty amounts; slots that failed carry the status "error".
+func respondRewardsCSV(c *gin.Context, results []models.SlotRewardResult) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(csvHeader)
+	for _, result := range results {
+		row := []string{strconv.FormatUint(result.Slot, 10), "error", "", "", ""}
+		if result.Error == "" && result.BlockReward != nil {
+			row[1] = result.Status
+			if rewardWei, ok := new(big.Int).SetString(result.RewardWei, 10); ok {
+				row[2] = formatUnits(rewardWei, "gwei")
+			}
+			row[3] = result.BurntFees
+			row[4] = result.FeeRecipient
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	c.Data(http.StatusOK, mimeCSV+"; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"eth-rewards-api/internal/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOpenAPISpec handles HTTP requests for the OpenAPI specification of the API.
+func GetOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", docs.OpenAPISpec)
+}
+
+// GetDocs handles HTTP requests for the interactive API documentation rendered by Swagger UI.
+func GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docs.SwaggerUIPage))
+}
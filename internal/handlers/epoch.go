@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"math/big"
+	"net/http"
+	"sort"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetEpochReward handles HTTP requests to retrieve the block rewards of every slot in an epoch together with their aggregate.
+// Missed slots are reported with the "missed" status; slots that fail or lie beyond the head are reported with an error.
+func (h *BlockRewardHandler) GetEpochReward(c *gin.Context) {
+	epoch, startSlot, results, ok := h.epochRewards(c)
+	if !ok {
+		return
+	}
+
+	// Aggregate the rewards and burnt fees of the proposed blocks.
+	resp := models.EpochReward{
+		Epoch:     epoch,
+		StartSlot: startSlot,
+		EndSlot:   startSlot + uint64(len(results)) - 1,
+		Slots:     results,
+	}
+	totalReward := big.NewInt(0)
+	totalBurntFees := big.NewInt(0)
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			resp.FailedSlots++
+		case result.Status == "pre_merge": // Slots before the merge have no execution-layer reward.
+			resp.PreMergeSlots++
+		case result.Status == "missed":
+			resp.MissedSlots++
+		case result.Status == "orphaned":
+			resp.OrphanedSlots++
+		default:
+			rewardWei, ok := new(big.Int).SetString(result.RewardWei, 10)
+			if !ok {
+				utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternalError, "invalid reward")
+				return
+			}
+			burntFeesWei, ok := new(big.Int).SetString(result.BurntFeesWei, 10)
+			if !ok {
+				utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternalError, "invalid burnt fees")
+				return
+			}
+			resp.ProposedBlocks++
+			totalReward.Add(totalReward, rewardWei)
+			totalBurntFees.Add(totalBurntFees, burntFeesWei)
+		}
+	}
+	resp.Reward = formatUnits(totalReward, "gwei")
+	resp.RewardWei = totalReward.String()
+	resp.BurntFees = formatUnits(totalBurntFees, "gwei")
+	resp.BurntFeesWei = totalBurntFees.String()
+
+	// Respond with the aggregate and the per-slot breakdown.
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetEpochRewardStats handles HTTP requests to retrieve summary statistics of the block rewards of an epoch:
+// the minimum, maximum, mean and median reward of its proposed blocks, and the number of relay and vanilla blocks
+// and of missed, orphaned, pre-merge and failed slots. The statistics are computed in wei, so no precision is lost.
+func (h *BlockRewardHandler) GetEpochRewardStats(c *gin.Context) {
+	epoch, startSlot, results, ok := h.epochRewards(c)
+	if !ok {
+		return
+	}
+
+	// Count the slots by outcome and collect the rewards of the proposed blocks.
+	resp := models.EpochRewardStats{
+		Epoch:     epoch,
+		StartSlot: startSlot,
+		EndSlot:   startSlot + uint64(len(results)) - 1,
+	}
+	var rewards []*big.Int
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			resp.FailedSlots++
+			continue
+		case result.Status == "pre_merge": // Slots before the merge have no execution-layer reward.
+			resp.PreMergeSlots++
+			continue
+		case result.Status == "missed":
+			resp.MissedSlots++
+			continue
+		case result.Status == "orphaned":
+			resp.OrphanedSlots++
+			continue
+		case result.Status == "relay":
+			resp.RelayBlocks++
+		default:
+			resp.VanillaBlocks++
+		}
+		rewardWei, ok := new(big.Int).SetString(result.RewardWei, 10)
+		if !ok {
+			utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternalError, "invalid reward")
+			return
+		}
+		rewards = append(rewards, rewardWei)
+	}
+	resp.ProposedBlocks = len(rewards)
+
+	// Summarize the rewards; an epoch without proposed blocks has no reward statistics.
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		n := len(rewards)
+
+		total := big.NewInt(0)
+		for _, reward := range rewards {
+			total.Add(total, reward)
+		}
+		mean := new(big.Int).Quo(total, big.NewInt(int64(n)))
+
+		// The median of an even number of rewards is the mean of the two middle ones.
+		median := new(big.Int).Set(rewards[n/2])
+		if n%2 == 0 {
+			median.Add(median, rewards[n/2-1])
+			median.Quo(median, big.NewInt(2))
+		}
+
+		resp.MinReward, resp.MinRewardWei = formatUnits(rewards[0], "gwei"), rewards[0].String()
+		resp.MaxReward, resp.MaxRewardWei = formatUnits(rewards[n-1], "gwei"), rewards[n-1].String()
+		resp.MeanReward, resp.MeanRewardWei = formatUnits(mean, "gwei"), mean.String()
+		resp.MedianReward, resp.MedianRewardWei = formatUnits(median, "gwei"), median.String()
+	}
+
+	// Respond with the statistics.
+	c.JSON(http.StatusOK, resp)
+}
+
+// epochRewards parses the epoch path parameter and computes the rewards of every slot of the epoch with the bounded
+// worker pool of the batch endpoint. It returns the epoch, its first slot and the results in slot order.
+// It writes the error response itself and returns false when the epoch is invalid, in the future or the head is unknown.
+func (h *BlockRewardHandler) epochRewards(c *gin.Context) (uint64, uint64, []models.SlotRewardResult, bool) {
+	// Parse the epoch parameter from the request URL.
+	epoch, err := utils.ParseUint(c.Param("epoch"))
+	if err != nil {
+		respondInvalidInput(c, "epoch", err)
+		return 0, 0, nil, false
+	}
+
+	// Ensure the requested epoch is not in the future by comparing it with the epoch of the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return 0, 0, nil, false
+	}
+	slotsPerEpoch := h.consensusService.SlotsPerEpoch()
+	if epoch > headSlot/slotsPerEpoch {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeEpochInFuture, "requested epoch is in the future")
+		return 0, 0, nil, false
+	}
+
+	// Compute the rewards of all slots of the epoch.
+	startSlot := epoch * slotsPerEpoch
+	slots := make([]uint64, slotsPerEpoch)
+	for i := range slots {
+		slots[i] = startSlot + uint64(i)
+	}
+	return epoch, startSlot, h.blockRewards(c.Request.Context(), slots, headSlot), true
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// immutableMaxAge is the max-age, in seconds, of responses that can never change: one year, the conventional maximum.
+const immutableMaxAge = 365 * 24 * 60 * 60
+
+// respondSlotData writes body as the JSON response for data derived from a single slot, with caching headers that
+// depend on the slot's finality. Data of a finalized slot can never change, so when the response is fixed, i.e. the
+// request identifies the slot itself rather than through an alias such as "head" or "finalized" and the data is
+// complete, the response carries an ETag and may be cached indefinitely. Everything else may be cached for one slot
+// only, since a reorg may still replace the block.
+// The ETag is weak, since the same data may be sent gzip-compressed or not, and responses vary by Accept-Encoding.
+// A request whose If-None-Match header matches the ETag is answered with 304 Not Modified and no body.
+func (h *BlockRewardHandler) respondSlotData(c *gin.Context, slot uint64, fixed bool, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		respondError(c, internalError("failed to encode response", err))
+		return
+	}
+	addVary(c.Writer.Header(), "Accept-Encoding")
+
+	if !fixed || !h.slotFinalized(c.Request.Context(), slot) {
+		c.Header("Cache-Control", fmt.Sprintf("max-age=%d", h.consensusService.Network().SecondsPerSlot))
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("max-age=%d, immutable", immutableMaxAge))
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// slotFinalized reports whether the slot is at or below the latest finalized slot, going through the reward cache's
+// throttled view of finality when the handler has one. Slots are treated as unfinalized when finality cannot be determined.
+func (h *BlockRewardHandler) slotFinalized(ctx context.Context, slot uint64) bool {
+	if h.rewardCache != nil {
+		return h.rewardCache.isFinalized(ctx, h.consensusService, slot)
+	}
+	finalizedSlot, err := h.consensusService.ResolveSlot(ctx, "finalized")
+	return err == nil && slot <= finalizedSlot
+}
+
+// etagMatches reports whether an If-None-Match header value matches the ETag. The header may list several ETags or
+// be "*"; as for any GET request, weak ETags are compared by their opaque value.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// addVary adds a request header to the Vary header of a response, unless it is listed already, e.g. by the gzip middleware.
+func addVary(header http.Header, name string) {
+	for _, value := range header.Values("Vary") {
+		for _, listed := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(listed), name) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", name)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// blockRewardFields is the set of field names a block reward response can carry, taken from the JSON tags of models.BlockReward.
+var blockRewardFields = jsonFieldNames(reflect.TypeOf(models.BlockReward{}))
+
+// jsonFieldNames returns the names the exported fields of a struct type are encoded under.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// parseFieldsParam parses the optional comma-separated fields query parameter, which selects the fields of the response
+// to return. It returns nil when the parameter is absent, meaning every field is returned.
+// It writes the error response itself and returns false when a field is not one of allowed.
+func parseFieldsParam(c *gin.Context, allowed map[string]bool) ([]string, bool) {
+	fieldsParam, ok := c.GetQuery("fields")
+	if !ok {
+		return nil, true
+	}
+
+	var fields []string
+	for _, field := range strings.Split(fieldsParam, ",") {
+		field = strings.TrimSpace(field)
+		if !allowed[field] {
+			names := make([]string, 0, len(allowed))
+			for name := range allowed {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, fmt.Sprintf("invalid fields parameter: unknown field %q", field), gin.H{"allowed": names})
+			return nil, false
+		}
+		fields = append(fields, field)
+	}
+	return fields, true
+}
+
+// projectFields returns the JSON object body is encoded as, reduced to the given fields. Fields the body omits, such as
+// those that do not apply to the block, stay absent. A nil list of fields returns body unchanged.
+func projectFields(body interface{}, fields []string) (interface{}, error) {
+	if fields == nil {
+		return body, nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := object[field]; ok {
+			projected[field] = value
+		}
+	}
+	return projected, nil
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds how long the readiness check waits for each upstream endpoint.
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler is a struct that serves the liveness and readiness probes.
+type HealthHandler struct {
+	consensusService ConsensusProvider
+	executionService ExecutionProvider
+}
+
+// NewHealthHandler initializes a new HealthHandler with the provided consensus-layer and execution-layer providers.
+func NewHealthHandler(cs ConsensusProvider, es ExecutionProvider) *HealthHandler {
+	return &HealthHandler{
+		consensusService: cs,
+		executionService: es,
+	}
+}
+
+// Healthz handles liveness probes. It always responds with 200 while the process is able to serve requests.
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// componentStatus reports the outcome of the readiness check of one upstream endpoint.
+type componentStatus struct {
+	Status string `json:"status"`           // "ok", "timeout" when the endpoint did not answer within readinessTimeout, or "unavailable".
+	Head   uint64 `json:"head,omitempty"`   // The head slot or latest block number the endpoint reported.
+	Reason string `json:"reason,omitempty"` // Why the check failed, without upstream details such as the endpoint URL.
+}
+
+// Readyz handles readiness probes by checking that the consensus endpoint answers a head slot request. The head slot is
+// always fetched from the endpoint rather than served from the head slot cache.
+// It responds with 503 when the endpoint is unreachable or does not answer within readinessTimeout.
+// With deep=true, the execution endpoint must also answer an eth_blockNumber call, and the status of both endpoints is
+// reported individually, which tells consensus-only from execution-only outages. The two checks run concurrently.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	if c.Query("deep") != "true" {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		defer cancel()
+
+		if _, err := h.consensusService.FetchHeadSlot(ctx); err != nil {
+			utils.RespondError(c, http.StatusServiceUnavailable, utils.CodeUnavailable, "consensus endpoint unreachable")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+		return
+	}
+
+	var consensus, execution componentStatus
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		consensus = checkComponent(c.Request.Context(), "consensus", h.consensusService.FetchHeadSlot)
+	}()
+	go func() {
+		defer wg.Done()
+		execution = checkComponent(c.Request.Context(), "execution", h.executionService.GetBlockNumber)
+	}()
+	wg.Wait()
+
+	components := gin.H{"consensus": consensus, "execution": execution}
+	if consensus.Status != "ok" || execution.Status != "ok" {
+		utils.RespondErrorWithDetails(c, http.StatusServiceUnavailable, utils.CodeUnavailable, "upstream endpoint unreachable", components)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "components": components})
+}
+
+// checkComponent runs the readiness check of one upstream endpoint within readinessTimeout. Failures are logged with
+// their cause, but only summarized in the returned status, since upstream errors may contain endpoint URLs.
+func checkComponent(ctx context.Context, component string, check func(context.Context) (uint64, error)) componentStatus {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	head, err := check(ctx)
+	switch {
+	case err == nil:
+		return componentStatus{Status: "ok", Head: head}
+	case isTimeout(err):
+		slog.WarnContext(ctx, "readiness check timed out", "component", component, "error", err)
+		return componentStatus{Status: "timeout", Reason: "no answer within " + readinessTimeout.String()}
+	default:
+		slog.WarnContext(ctx, "readiness check failed", "component", component, "error", err)
+		return componentStatus{Status: "unavailable", Reason: component + " endpoint unreachable"}
+	}
+}
@@ -0,0 +1,951 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gwei is one gwei in wei.
+const gwei = 1_000_000_000
+
+// testTx describes a canned transaction together with the receipt the execution client reports for it.
+type testTx struct {
+	txType            string // "0x0" for legacy, "0x2" for dynamic fee transactions.
+	gasPrice          uint64 // The gas price of a legacy transaction.
+	maxFee            uint64 // The max fee per gas of a dynamic fee transaction.
+	maxPriorityFee    uint64 // The max priority fee per gas of a dynamic fee transaction.
+	gasUsed           uint64
+	effectiveGasPrice uint64
+}
+
+// testBlock describes a canned post-merge block: the beacon block of a slot and the execution block of its payload.
+type testBlock struct {
+	slot          uint64
+	number        uint64
+	proposerIndex string
+	feeRecipient  string
+	baseFee       uint64
+	gasLimit      uint64
+	txs           []testTx
+	balanceBefore *big.Int // The balance of the fee recipient as of the parent block.
+	balanceAfter  *big.Int // The balance of the fee recipient as of the block.
+}
+
+// gasUsed returns the gas used by all transactions of the block.
+func (b testBlock) gasUsed() uint64 {
+	var total uint64
+	for _, tx := range b.txs {
+		total += tx.gasUsed
+	}
+	return total
+}
+
+// hash returns the made-up hash of the execution block.
+func (b testBlock) hash() string {
+	return fmt.Sprintf("0x%064x", b.number)
+}
+
+// beaconBlock returns the beacon block in the format of the beacon API.
+func (b testBlock) beaconBlock() any {
+	return map[string]any{
+		"version": "capella",
+		"data": map[string]any{
+			"message": map[string]any{
+				"slot":           strconv.FormatUint(b.slot, 10),
+				"proposer_index": b.proposerIndex,
+				"parent_root":    fmt.Sprintf("0x%064x", b.slot-1),
+				"state_root":     stateRoot(b.slot),
+				"body": map[string]any{
+					"execution_payload": map[string]any{
+						"block_number":     strconv.FormatUint(b.number, 10),
+						"block_hash":       b.hash(),
+						"fee_recipient":    b.feeRecipient,
+						"extra_data":       "0x",
+						"base_fee_per_gas": strconv.FormatUint(b.baseFee, 10),
+						"gas_used":         strconv.FormatUint(b.gasUsed(), 10),
+					},
+				},
+			},
+		},
+	}
+}
+
+// execBlock returns the execution block in the format of eth_getBlockByNumber, with full transactions or their hashes only.
+func (b testBlock) execBlock(fullTransactions bool) any {
+	txs := make([]any, len(b.txs))
+	for i, tx := range b.txs {
+		if !fullTransactions {
+			txs[i] = b.txHash(i)
+			continue
+		}
+		fields := map[string]any{
+			"hash":             b.txHash(i),
+			"blockNumber":      hexQuantity(b.number),
+			"transactionIndex": hexQuantity(uint64(i)),
+			"type":             tx.txType,
+			"from":             "0x1111111111111111111111111111111111111111",
+			"to":               "0x2222222222222222222222222222222222222222",
+			"value":            "0x0",
+			"gas":              hexQuantity(tx.gasUsed),
+		}
+		if tx.txType == "0x2" {
+			fields["maxFeePerGas"] = hexQuantity(tx.maxFee)
+			fields["maxPriorityFeePerGas"] = hexQuantity(tx.maxPriorityFee)
+			fields["gasPrice"] = hexQuantity(tx.effectiveGasPrice)
+		} else {
+			fields["gasPrice"] = hexQuantity(tx.gasPrice)
+		}
+		txs[i] = fields
+	}
+	return map[string]any{
+		"number":        hexQuantity(b.number),
+		"hash":          b.hash(),
+		"parentHash":    fmt.Sprintf("0x%064x", b.number-1),
+		"timestamp":     "0x0",
+		"gasUsed":       hexQuantity(b.gasUsed()),
+		"gasLimit":      hexQuantity(b.gasLimit),
+		"baseFeePerGas": hexQuantity(b.baseFee),
+		"extraData":     "0x",
+		"transactions":  txs,
+	}
+}
+
+// receipts returns the receipts of the block's transactions in the format of eth_getBlockReceipts.
+func (b testBlock) receipts() any {
+	receipts := make([]any, len(b.txs))
+	for i, tx := range b.txs {
+		receipts[i] = map[string]any{
+			"transactionHash":   b.txHash(i),
+			"transactionIndex":  hexQuantity(uint64(i)),
+			"from":              "0x1111111111111111111111111111111111111111",
+			"to":                "0x2222222222222222222222222222222222222222",
+			"type":              tx.txType,
+			"gasUsed":           hexQuantity(tx.gasUsed),
+			"effectiveGasPrice": hexQuantity(tx.effectiveGasPrice),
+			"status":            "0x1",
+		}
+	}
+	return receipts
+}
+
+// txHash returns the made-up hash of the i-th transaction of the block.
+func (b testBlock) txHash(i int) string {
+	return fmt.Sprintf("0x%056x%08x", b.number, i)
+}
+
+// hexQuantity formats a number as a JSON-RPC quantity.
+func hexQuantity(n uint64) string {
+	return fmt.Sprintf("0x%x", n)
+}
+
+// testChain holds the canned chain served by the mock beacon node and execution client.
+type testChain struct {
+	head           uint64
+	finalized      uint64
+	blocks         map[uint64]testBlock // The blocks keyed by slot; slots without one are missed.
+	syncCommittees map[uint64][]string  // The sync committee validators keyed by period.
+
+	mu         sync.Mutex
+	syncStates []string       // The state ids the sync committees were requested from, in order.
+	lagging    map[string]int // The number of further requests per method answered as if no block had been imported yet.
+}
+
+// lags reports whether a request for the method is answered as if the execution client lagged, counting the request.
+func (tc *testChain) lags(method string) bool {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.lagging[method] == 0 {
+		return false
+	}
+	tc.lagging[method]--
+	return true
+}
+
+// stateRoot returns the state root of the canned block at a slot.
+func stateRoot(slot uint64) string {
+	return fmt.Sprintf("0x%063xf", slot)
+}
+
+// header returns a beacon header of a slot in the format of the headers endpoints.
+func header(slot uint64) map[string]any {
+	return map[string]any{
+		"root":      fmt.Sprintf("0x%064x", slot),
+		"canonical": true,
+		"header": map[string]any{
+			"message": map[string]any{
+				"slot":           strconv.FormatUint(slot, 10),
+				"proposer_index": "1",
+				"parent_root":    fmt.Sprintf("0x%064x", slot-1),
+				"state_root":     stateRoot(slot),
+			},
+		},
+	}
+}
+
+// serveBeacon answers the beacon API requests made by the consensus service from the canned chain.
+func (tc *testChain) serveBeacon(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case path == "/eth/v1/beacon/headers" && r.URL.Query().Has("slot"):
+		slot, _ := strconv.ParseUint(r.URL.Query().Get("slot"), 10, 64)
+		headers := []any{}
+		if _, ok := tc.blocks[slot]; ok {
+			headers = append(headers, header(slot))
+		}
+		writeJSON(w, map[string]any{"data": headers})
+	case path == "/eth/v1/beacon/headers":
+		writeJSON(w, map[string]any{"data": []any{header(tc.head)}})
+	case path == "/eth/v1/beacon/headers/head":
+		writeJSON(w, map[string]any{"data": header(tc.head)})
+	case path == "/eth/v1/beacon/headers/finalized":
+		writeJSON(w, map[string]any{"data": header(tc.finalized)})
+	case strings.HasPrefix(path, "/eth/v1/beacon/headers/"):
+		slot, err := strconv.ParseUint(strings.TrimPrefix(path, "/eth/v1/beacon/headers/"), 10, 64)
+		if _, ok := tc.blocks[slot]; err != nil || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, map[string]any{"data": header(slot)})
+	case strings.HasPrefix(path, "/eth/v2/beacon/blocks/"):
+		slot, err := strconv.ParseUint(strings.TrimPrefix(path, "/eth/v2/beacon/blocks/"), 10, 64)
+		block, ok := tc.blocks[slot]
+		if err != nil || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, block.beaconBlock())
+	case strings.HasPrefix(path, "/eth/v1/beacon/states/") && strings.HasSuffix(path, "/sync_committees"):
+		stateID := strings.TrimSuffix(strings.TrimPrefix(path, "/eth/v1/beacon/states/"), "/sync_committees")
+		tc.mu.Lock()
+		tc.syncStates = append(tc.syncStates, stateID)
+		tc.mu.Unlock()
+		epoch, _ := strconv.ParseUint(r.URL.Query().Get("epoch"), 10, 64)
+		validators, ok := tc.syncCommittees[epoch/services.EPOCHS_PER_SYNC_COMMITTEE_PERIOD]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, map[string]any{"data": map[string]any{"validators": validators}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// testRPCRequest is a JSON-RPC request received by the mock execution client.
+type testRPCRequest struct {
+	ID     int               `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// serveExecution answers the JSON-RPC requests, single or batched, made by the execution service from the canned chain.
+func (tc *testChain) serveExecution(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		var reqs []testRPCRequest
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resps := make([]any, len(reqs))
+		for i, req := range reqs {
+			resps[i] = tc.answerRPC(req)
+		}
+		writeJSON(w, resps)
+		return
+	}
+	var req testRPCRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, tc.answerRPC(req))
+}
+
+// answerRPC returns the JSON-RPC response to a single request. Unknown blocks are answered with a null result.
+func (tc *testChain) answerRPC(req testRPCRequest) any {
+	param := func(i int) string {
+		var s string
+		if i < len(req.Params) {
+			json.Unmarshal(req.Params[i], &s)
+		}
+		return s
+	}
+	blockByNumber := func(hex string) (testBlock, bool) {
+		for _, block := range tc.blocks {
+			if hexQuantity(block.number) == hex {
+				return block, true
+			}
+		}
+		return testBlock{}, false
+	}
+
+	var result any
+	switch req.Method {
+	case "eth_getBlockByNumber":
+		var full bool
+		if len(req.Params) > 1 {
+			json.Unmarshal(req.Params[1], &full)
+		}
+		if block, ok := blockByNumber(param(0)); ok && !tc.lags(req.Method) {
+			result = block.execBlock(full)
+		}
+	case "eth_getBlockReceipts":
+		if block, ok := blockByNumber(param(0)); ok && !tc.lags(req.Method) {
+			result = block.receipts()
+		}
+	case "eth_getBalance":
+		for _, block := range tc.blocks {
+			if !strings.EqualFold(block.feeRecipient, param(0)) {
+				continue
+			}
+			if block.balanceBefore == nil {
+				// Like a node without the state of old blocks.
+				return map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": map[string]any{"code": -32000, "message": "missing trie node"}}
+			}
+			switch param(1) {
+			case hexQuantity(block.number - 1):
+				result = "0x" + block.balanceBefore.Text(16)
+			case hexQuantity(block.number):
+				result = "0x" + block.balanceAfter.Text(16)
+			}
+		}
+	case "eth_chainId":
+		result = "0x1"
+	default:
+		return map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": map[string]any{"code": -32601, "message": "method not found"}}
+	}
+	return map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result}
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// newTestHandler starts the mock upstreams serving the chain and returns a handler in front of them.
+// The options are applied to the execution service.
+func newTestHandler(t *testing.T, tc *testChain, opts ...services.Option) *BlockRewardHandler {
+	t.Helper()
+	beacon := httptest.NewServer(http.HandlerFunc(tc.serveBeacon))
+	t.Cleanup(beacon.Close)
+	execution := httptest.NewServer(http.HandlerFunc(tc.serveExecution))
+	t.Cleanup(execution.Close)
+
+	cs := services.NewConsensusService(beacon.URL, services.WithRetryPolicy(services.NoRetry))
+	es := services.NewExecutionService(execution.URL, append([]services.Option{services.WithRetryPolicy(services.NoRetry)}, opts...)...)
+	return NewBlockRewardHandler(cs, es)
+}
+
+// newTestRouter returns a router serving the API of the handler.
+func newTestRouter(h *BlockRewardHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h.RegisterRoutes(r)
+	return r
+}
+
+// serve sends a request to the router and returns the recorded response.
+func serve(r http.Handler, method, target string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, body)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decodeJSON decodes the body of a response, failing the test if it is not valid JSON.
+// Numbers are kept as json.Number, so that large integers compare by their digits.
+func decodeJSON(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(w.Body.Bytes()))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", w.Body.String(), err)
+	}
+}
+
+// checkFields fails the test for every expected field whose value in the decoded response differs.
+func checkFields(t *testing.T, got map[string]any, want map[string]any) {
+	t.Helper()
+	for field, value := range want {
+		if fmt.Sprint(got[field]) != fmt.Sprint(value) {
+			t.Errorf("%s = %v, want %v", field, got[field], value)
+		}
+	}
+}
+
+// ether returns the given number of ether in wei.
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1_000_000_000_000_000_000))
+}
+
+// newTestChain returns a chain with a vanilla block, a block paying its proposer through a builder, a missed slot and
+// a single sync committee period.
+func newTestChain() *testChain {
+	vanilla := testBlock{
+		slot:          9_000_000,
+		number:        20_000_000,
+		proposerIndex: "12345",
+		feeRecipient:  "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		baseFee:       10 * gwei,
+		gasLimit:      30_000_000,
+		txs: []testTx{
+			// Pays min(2, 30 - 10) = 2 gwei per gas above the base fee.
+			{txType: "0x2", maxFee: 30 * gwei, maxPriorityFee: 2 * gwei, gasUsed: 21_000, effectiveGasPrice: 12 * gwei},
+			// Pays 15 - 10 = 5 gwei per gas above the base fee.
+			{txType: "0x0", gasPrice: 15 * gwei, gasUsed: 50_000, effectiveGasPrice: 15 * gwei},
+		},
+		balanceBefore: ether(1),
+	}
+	// The fee recipient earns exactly the priority fees: 21000 * 2 + 50000 * 5 = 292000 gwei.
+	vanilla.balanceAfter = new(big.Int).Add(vanilla.balanceBefore, big.NewInt(292_000*gwei))
+
+	builder := testBlock{
+		slot:          9_000_001,
+		number:        20_000_001,
+		proposerIndex: "23456",
+		feeRecipient:  "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		baseFee:       8 * gwei,
+		gasLimit:      30_000_000,
+		txs: []testTx{
+			// Pays 9 - 8 = 1 gwei per gas above the base fee.
+			{txType: "0x2", maxFee: 20 * gwei, maxPriorityFee: 1 * gwei, gasUsed: 100_000, effectiveGasPrice: 9 * gwei},
+		},
+		balanceBefore: ether(2),
+	}
+	// The fee recipient earns the 100000 gwei of priority fees and a payment of 0.05 ether from the builder.
+	builder.balanceAfter = new(big.Int).Add(builder.balanceBefore, big.NewInt(100_000*gwei+50_000_000*gwei))
+
+	return &testChain{
+		head:      9_000_010,
+		finalized: 9_000_000,
+		blocks: map[uint64]testBlock{
+			vanilla.slot: vanilla,
+			builder.slot: builder,
+			// Slot 9000002 is missed.
+		},
+		syncCommittees: map[uint64][]string{
+			9_000_000 / (services.SLOTS_PER_EPOCH * services.EPOCHS_PER_SYNC_COMMITTEE_PERIOD): {"101", "202", "303", "404"},
+		},
+	}
+}
+
+func TestIntegrationBlockReward(t *testing.T) {
+	r := newTestRouter(newTestHandler(t, newTestChain()))
+
+	tests := []struct {
+		name   string
+		target string
+		want   map[string]any
+	}{
+		{
+			name:   "vanilla block",
+			target: "/blockreward/9000000",
+			want: map[string]any{
+				"status":             "vanilla",
+				"reward":             "292000",
+				"unit":               "gwei",
+				"reward_wei":         "292000000000000",
+				"burnt_fees":         "710000",
+				"burnt_fees_wei":     "710000000000000",
+				"tx_count":           2,
+				"gas_used":           "71000",
+				"gas_limit":          "30000000",
+				"gas_utilization":    "0.23",
+				"mev_payment_wei":    "0",
+				"priority_fee_total": "292000",
+				"block_value_total":  "292000",
+				"block_value_source": "priority_fees",
+				"block_number":       "20000000",
+				"fee_recipient":      "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				"proposer_index":     "12345",
+				"epoch":              281250,
+				"slot_in_epoch":      0,
+			},
+		},
+		{
+			name:   "vanilla block in eth",
+			target: "/blockreward/9000000?unit=eth",
+			want: map[string]any{
+				"reward":     "0.000292000",
+				"unit":       "eth",
+				"reward_wei": "292000000000000",
+			},
+		},
+		{
+			name:   "builder payment",
+			target: "/blockreward/9000001",
+			want: map[string]any{
+				"status":             "relay",
+				"reward":             "100000",
+				"reward_wei":         "100000000000000",
+				"burnt_fees_wei":     "800000000000000",
+				"tx_count":           1,
+				"gas_utilization":    "0.33",
+				"mev_payment":        "50000000",
+				"mev_payment_wei":    "50000000000000000",
+				"block_value_total":  "50100000",
+				"block_value_source": "builder_payment",
+				"proposer_index":     "23456",
+				"slot_in_epoch":      1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, tt.target, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+			}
+			var got map[string]any
+			decodeJSON(t, w, &got)
+			checkFields(t, got, tt.want)
+		})
+	}
+}
+
+func TestIntegrationBlockRewardErrors(t *testing.T) {
+	r := newTestRouter(newTestHandler(t, newTestChain()))
+
+	tests := []struct {
+		name     string
+		target   string
+		wantCode int
+		want     string
+	}{
+		{"missed slot", "/blockreward/9000002", http.StatusNotFound, "SLOT_MISSED"},
+		{"future slot", "/blockreward/9000011", http.StatusBadRequest, "SLOT_IN_FUTURE"},
+		{"invalid slot", "/blockreward/abc", http.StatusBadRequest, "INVALID_INPUT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, tt.target, nil)
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body)
+			}
+			var got map[string]any
+			decodeJSON(t, w, &got)
+			if got["code"] != tt.want {
+				t.Errorf("code = %v, want %s", got["code"], tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegrationBlockRewardCaching(t *testing.T) {
+	r := newTestRouter(newTestHandler(t, newTestChain()))
+
+	// The finalized slot may be cached for good, and revalidated through its ETag.
+	// The ETag is weak, since the response may be sent compressed or not.
+	w := serve(r, http.MethodGet, "/blockreward/9000000", nil)
+	etag := w.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) || !strings.Contains(w.Header().Get("Cache-Control"), "immutable") {
+		t.Fatalf("finalized slot: ETag = %q, Cache-Control = %q, want an immutable response with a weak ETag", etag, w.Header().Get("Cache-Control"))
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("finalized slot: Vary = %q, want %q", got, "Accept-Encoding")
+	}
+	req := httptest.NewRequest(http.MethodGet, "/blockreward/9000000", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("revalidation status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+
+	// A slot after the finalized one may still be reorged, so it is cached for one slot only.
+	w = serve(r, http.MethodGet, "/blockreward/9000001", nil)
+	if got := w.Header().Get("Cache-Control"); got != "max-age=12" {
+		t.Errorf("unfinalized slot: Cache-Control = %q, want %q", got, "max-age=12")
+	}
+	if w.Header().Get("ETag") != "" {
+		t.Errorf("unfinalized slot: unexpected ETag %q", w.Header().Get("ETag"))
+	}
+}
+
+func TestIntegrationPartialRewardNotCached(t *testing.T) {
+	tc := newTestChain()
+	// The fee recipient's balances are unavailable, so the MEV payment of the finalized slot cannot be measured.
+	block := tc.blocks[9_000_000]
+	block.balanceBefore, block.balanceAfter = nil, nil
+	tc.blocks[9_000_000] = block
+	h := newTestHandler(t, tc)
+	WithRewardCache(16)(h)
+	r := newTestRouter(h)
+
+	for i := 0; i < 2; i++ {
+		w := serve(r, http.MethodGet, "/blockreward/9000000", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+		}
+		var got map[string]any
+		decodeJSON(t, w, &got)
+		checkFields(t, got, map[string]any{"reward_wei": "292000000000000"})
+		if _, ok := got["mev_payment_wei"]; ok {
+			t.Errorf("unexpected mev_payment_wei %v", got["mev_payment_wei"])
+		}
+		if etag := w.Header().Get("ETag"); etag != "" {
+			t.Errorf("partial reward: unexpected ETag %q", etag)
+		}
+		if got := w.Header().Get("Cache-Control"); got != "max-age=12" {
+			t.Errorf("partial reward: Cache-Control = %q, want %q", got, "max-age=12")
+		}
+	}
+
+	// Both requests computed the reward, since the partial reward was not stored.
+	if hits, misses := h.RewardCacheStats(); hits != 0 || misses != 2 {
+		t.Errorf("reward cache hits = %d, misses = %d, want 0 and 2", hits, misses)
+	}
+}
+
+func TestIntegrationBlockRewardBatch(t *testing.T) {
+	for _, hashesOnly := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hashes only %t", hashesOnly), func(t *testing.T) {
+			r := newTestRouter(newTestHandler(t, newTestChain(), services.WithTransactionHashesOnly(hashesOnly)))
+
+			w := serve(r, http.MethodPost, "/blockreward/batch", strings.NewReader(`{"slots":[9000000,9000001,9000002,9000011]}`))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+			}
+			var got []map[string]any
+			decodeJSON(t, w, &got)
+			want := []map[string]any{
+				{"slot": 9000000, "status": "vanilla", "reward": "292000", "reward_wei": "292000000000000", "burnt_fees_wei": "710000000000000", "tx_count": 2, "mev_payment_wei": "0"},
+				{"slot": 9000001, "status": "relay", "reward": "100000", "reward_wei": "100000000000000", "mev_payment_wei": "50000000000000000", "block_value_total": "50100000"},
+				{"slot": 9000002, "status": "missed"},
+				{"slot": 9000011, "code": "SLOT_IN_FUTURE"},
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d results, want %d: %s", len(got), len(want), w.Body)
+			}
+			for i := range want {
+				checkFields(t, got[i], want[i])
+			}
+		})
+	}
+}
+
+func TestIntegrationPreMergeSlot(t *testing.T) {
+	r := newTestRouter(newTestHandler(t, newTestChain()))
+
+	w := serve(r, http.MethodGet, "/blockreward/100", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var got map[string]any
+	decodeJSON(t, w, &got)
+	want := map[string]any{"slot": 100, "status": "pre_merge", "code": "PRE_MERGE_SLOT"}
+	checkFields(t, got, want)
+	if reward, ok := got["reward"]; !ok || reward != nil {
+		t.Errorf("reward = %v, want null", reward)
+	}
+
+	w = serve(r, http.MethodPost, "/blockreward/batch", strings.NewReader(`{"slots":[100,9000000]}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("batch status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var results []map[string]any
+	decodeJSON(t, w, &results)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %s", len(results), w.Body)
+	}
+	checkFields(t, results[0], want)
+	if reward, ok := results[0]["reward"]; !ok || reward != nil {
+		t.Errorf("batch reward = %v, want null", reward)
+	}
+	checkFields(t, results[1], map[string]any{"slot": 9000000, "status": "vanilla", "reward": "292000"})
+
+	// Epoch 3 lies wholly before the merge: its slots are counted as pre-merge rather than failed.
+	for _, target := range []string{"/epochreward/3", "/epochreward/3/stats"} {
+		w = serve(r, http.MethodGet, target, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want %d: %s", target, w.Code, http.StatusOK, w.Body)
+		}
+		var epoch map[string]any
+		decodeJSON(t, w, &epoch)
+		checkFields(t, epoch, map[string]any{"proposed_blocks": 0, "pre_merge_slots": 32, "failed_slots": 0})
+	}
+}
+
+func TestIntegrationExecutionLag(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string // The lagging JSON-RPC method.
+		lagging int    // The requests answered with a null result.
+		request func(r http.Handler) *httptest.ResponseRecorder
+	}{
+		{"block", "eth_getBlockByNumber", 2, func(r http.Handler) *httptest.ResponseRecorder {
+			return serve(r, http.MethodGet, "/blockreward/9000000", nil)
+		}},
+		{"receipts", "eth_getBlockReceipts", 2, func(r http.Handler) *httptest.ResponseRecorder {
+			return serve(r, http.MethodGet, "/blockreward/9000000", nil)
+		}},
+		{"batched block", "eth_getBlockByNumber", 3, func(r http.Handler) *httptest.ResponseRecorder {
+			return serve(r, http.MethodPost, "/blockreward/batch", strings.NewReader(`{"slots":[9000000,9000001]}`))
+		}},
+		{"batched receipts", "eth_getBlockReceipts", 3, func(r http.Handler) *httptest.ResponseRecorder {
+			return serve(r, http.MethodPost, "/blockreward/batch", strings.NewReader(`{"slots":[9000000,9000001]}`))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTestChain()
+			tc.head = 9_000_003 // The slots were just proposed.
+			tc.lagging = map[string]int{tt.method: tt.lagging}
+			h := newTestHandler(t, tc)
+			WithExecutionLagRetry(3, time.Millisecond)(h)
+
+			w := tt.request(newTestRouter(h))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+			}
+			var got []map[string]any
+			if bytes.HasPrefix(w.Body.Bytes(), []byte("[")) {
+				decodeJSON(t, w, &got)
+			} else {
+				got = make([]map[string]any, 1)
+				decodeJSON(t, w, &got[0])
+			}
+			for _, result := range got {
+				if result["code"] != nil || result["reward"] == nil {
+					t.Errorf("result = %v, want a reward", result)
+				}
+			}
+			if tc.lagging[tt.method] != 0 {
+				t.Errorf("%d lagging requests left, want 0", tc.lagging[tt.method])
+			}
+		})
+	}
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		tc := newTestChain()
+		tc.head = 9_000_003
+		tc.lagging = map[string]int{"eth_getBlockReceipts": 10}
+		h := newTestHandler(t, tc)
+		WithExecutionLagRetry(2, time.Millisecond)(h)
+
+		w := serve(newTestRouter(h), http.MethodPost, "/blockreward/batch", strings.NewReader(`{"slots":[9000000]}`))
+		var got []map[string]any
+		decodeJSON(t, w, &got)
+		if len(got) != 1 || got[0]["code"] == nil {
+			t.Fatalf("results = %s, want a failure", w.Body)
+		}
+		if tc.lagging["eth_getBlockReceipts"] != 7 {
+			t.Errorf("%d lagging requests left, want 7 after a lookup and two retries", tc.lagging["eth_getBlockReceipts"])
+		}
+	})
+
+	// Slots further from the head are not retried: the execution client is expected to have their blocks already.
+	historical := []struct {
+		name    string
+		request func(r http.Handler) *httptest.ResponseRecorder
+	}{
+		{"historical block", func(r http.Handler) *httptest.ResponseRecorder {
+			return serve(r, http.MethodGet, "/blockreward/9000000", nil)
+		}},
+		{"historical batched block", func(r http.Handler) *httptest.ResponseRecorder {
+			return serve(r, http.MethodPost, "/blockreward/batch", strings.NewReader(`{"slots":[9000000]}`))
+		}},
+	}
+	for _, tt := range historical {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTestChain()
+			tc.lagging = map[string]int{"eth_getBlockByNumber": 10}
+			h := newTestHandler(t, tc)
+			WithExecutionLagRetry(3, time.Millisecond)(h)
+
+			w := tt.request(newTestRouter(h))
+			if !strings.Contains(w.Body.String(), utils.CodeUpstreamError) {
+				t.Errorf("response = %s, want an upstream error", w.Body)
+			}
+			if tc.lagging["eth_getBlockByNumber"] != 9 {
+				t.Errorf("%d lagging requests left, want 9 after a single lookup", tc.lagging["eth_getBlockByNumber"])
+			}
+		})
+	}
+}
+
+func TestIntegrationSyncDuties(t *testing.T) {
+	tc := newTestChain()
+	r := newTestRouter(newTestHandler(t, tc))
+
+	w := serve(r, http.MethodGet, "/syncduties/9000000", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var got map[string]any
+	decodeJSON(t, w, &got)
+	checkFields(t, got, map[string]any{
+		"validators":    []string{"101", "202", "303", "404"},
+		"total":         4,
+		"epoch":         281250,
+		"slot_in_epoch": 0,
+	})
+
+	// The committee is read from the state at the first slot of the period: epoch 1098 * 256, slot 281088 * 32.
+	if len(tc.syncStates) != 1 || tc.syncStates[0] != "8994816" {
+		t.Errorf("sync committee requested from states %v, want [8994816]", tc.syncStates)
+	}
+
+	// A page of the committee.
+	w = serve(r, http.MethodGet, "/syncduties/9000000?offset=1&limit=2", nil)
+	decodeJSON(t, w, &got)
+	checkFields(t, got, map[string]any{"validators": []string{"202", "303"}, "total": 4})
+}
+
+func TestIntegrationEmptyBlock(t *testing.T) {
+	tc := newTestChain()
+	// A block without transactions: the execution client answers an empty transactions array and empty receipts.
+	// Its fee recipient has no balance history, so asking for balances would fail the payment measurement.
+	tc.blocks[9_000_003] = testBlock{
+		slot:          9_000_003,
+		number:        20_000_002,
+		proposerIndex: "34567",
+		feeRecipient:  "0xcccccccccccccccccccccccccccccccccccccccc",
+		baseFee:       7 * gwei,
+		gasLimit:      30_000_000,
+	}
+	r := newTestRouter(newTestHandler(t, tc))
+
+	want := map[string]any{
+		"status":             "vanilla",
+		"reward":             "0",
+		"reward_wei":         "0",
+		"burnt_fees_wei":     "0",
+		"tx_count":           0,
+		"gas_used":           "0",
+		"gas_utilization":    "0",
+		"mev_payment_wei":    "0",
+		"block_value_total":  "0",
+		"block_value_source": "priority_fees",
+	}
+
+	w := serve(r, http.MethodGet, "/blockreward/9000003", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var got map[string]any
+	decodeJSON(t, w, &got)
+	checkFields(t, got, want)
+
+	w = serve(r, http.MethodPost, "/blockreward/batch", strings.NewReader(`{"slots":[9000003]}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("batch status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var results []map[string]any
+	decodeJSON(t, w, &results)
+	if len(results) != 1 {
+		t.Fatalf("got %d batch results, want 1: %s", len(results), w.Body)
+	}
+	checkFields(t, results[0], want)
+}
+
+func TestIntegrationUpstreamOverride(t *testing.T) {
+	h := newTestHandler(t, newTestChain())
+
+	// The alternative node has a block in slot 9000002, which the configured node missed.
+	alternative := newTestChain()
+	block := alternative.blocks[9_000_000]
+	block.slot, block.number = 9_000_002, 20_000_002
+	alternative.blocks[block.slot] = block
+	node := newTestHandler(t, alternative)
+	WithUpstreamOverrides("s3cret",
+		map[string]ConsensusProvider{"node-b": node.consensusService},
+		map[string]ExecutionProvider{"node-b": node.executionService})(h)
+	r := newTestRouter(h)
+
+	tests := []struct {
+		name      string
+		consensus string
+		execution string
+		token     string
+		wantCode  int
+	}{
+		{"configured upstreams", "", "", "", http.StatusNotFound},
+		{"overridden upstreams", "node-b", "node-b", "s3cret", http.StatusOK},
+		{"missing token", "node-b", "node-b", "", http.StatusForbidden},
+		{"invalid token", "node-b", "node-b", "guess", http.StatusForbidden},
+		{"unknown alias", "node-c", "", "s3cret", http.StatusForbidden},
+		{"endpoint URL instead of alias", "https://node-b.example.com", "", "s3cret", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/blockreward/9000002", nil)
+			if tt.consensus != "" {
+				req.Header.Set(headerUpstreamConsensus, tt.consensus)
+			}
+			if tt.execution != "" {
+				req.Header.Set(headerUpstreamExecution, tt.execution)
+			}
+			if tt.token != "" {
+				req.Header.Set(headerUpstreamToken, tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body)
+			}
+			if tt.wantCode == http.StatusForbidden {
+				var got map[string]any
+				decodeJSON(t, w, &got)
+				if got["code"] != utils.CodeOverrideDenied {
+					t.Errorf("code = %v, want %s", got["code"], utils.CodeOverrideDenied)
+				}
+			}
+		})
+	}
+}
+
+func TestIntegrationReadyzBypassesHeadSlotCache(t *testing.T) {
+	tc := newTestChain()
+	beacon := httptest.NewServer(http.HandlerFunc(tc.serveBeacon))
+	defer beacon.Close()
+	execution := httptest.NewServer(http.HandlerFunc(tc.serveExecution))
+	defer execution.Close()
+
+	cs := services.NewConsensusService(beacon.URL, services.WithRetryPolicy(services.NoRetry), services.WithHeadSlotTTL(time.Hour))
+	es := services.NewExecutionService(execution.URL, services.WithRetryPolicy(services.NoRetry))
+	h := NewHealthHandler(cs, es)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/readyz", h.Readyz)
+
+	if w := serve(r, http.MethodGet, "/readyz", nil); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	// Once the beacon node is gone, readiness fails although the head slot is still cached.
+	if _, err := cs.GetHeadSlot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	beacon.Close()
+	if _, err := cs.GetHeadSlot(context.Background()); err != nil {
+		t.Fatalf("cached head slot: %v", err)
+	}
+	for _, target := range []string{"/readyz", "/readyz?deep=true"} {
+		if w := serve(r, http.MethodGet, target, nil); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s status = %d, want %d: %s", target, w.Code, http.StatusServiceUnavailable, w.Body)
+		}
+	}
+}
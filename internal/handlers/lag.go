@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+)
+
+// executionLagSlots is how close to the head a slot must be for its missing execution block to be put down to a lagging
+// execution client. The execution client is expected to have the blocks of older slots, so their lookups are not retried.
+const executionLagSlots = 4
+
+// executionLagRetry bounds the retries made when the execution client does not know the block of a beacon block yet.
+type executionLagRetry struct {
+	attempts int           // The number of retries after the first lookup; 0 disables retrying.
+	delay    time.Duration // The delay before each retry.
+}
+
+// WithExecutionLagRetry retries the lookup of an execution block, or of its receipts, up to attempts times, waiting delay before each retry,
+// when the beacon block of a slot exists but the execution client does not have its payload's block yet.
+// This bridges the moment after a block is proposed in which the execution client lags the beacon node, so only the
+// lookups for slots within a few slots of the head are retried. By default the lookup is not retried.
+func WithExecutionLagRetry(attempts int, delay time.Duration) HandlerOption {
+	return func(h *BlockRewardHandler) {
+		h.executionLag = executionLagRetry{attempts: attempts, delay: delay}
+	}
+}
+
+// payloadExecutionBlock retrieves the execution block of the payload of the beacon block of a slot. Since the beacon
+// block exists, a missing execution block of a recent slot means the execution client lags behind, so the lookup is
+// retried as configured.
+func (h *BlockRewardHandler) payloadExecutionBlock(ctx context.Context, slot uint64, blockNumberHex string) (*models.ExecutionBlockFullResponse, error) {
+	var execBlock *models.ExecutionBlockFullResponse
+	err := h.retryLagging(ctx, slot, blockNumberHex, func() (err error) {
+		execBlock, err = h.executionService.GetExecutionBlockByNumber(ctx, blockNumberHex)
+		return err
+	})
+	return execBlock, err
+}
+
+// payloadReceipts retrieves the receipts of the payload of the beacon block of a slot, retrying as configured while the
+// execution client has not processed the block of a recent slot yet.
+func (h *BlockRewardHandler) payloadReceipts(ctx context.Context, slot uint64, blockNumberHex string) ([]models.TransactionReceipt, error) {
+	var receipts []models.TransactionReceipt
+	err := h.retryLagging(ctx, slot, blockNumberHex, func() (err error) {
+		receipts, err = h.executionService.GetBlockReceipts(ctx, blockNumberHex)
+		return err
+	})
+	return receipts, err
+}
+
+// payloadBlocksWithReceipts retrieves the execution blocks and receipts of the payloads of the given slots in batched
+// calls. The blocks of recent slots the execution client does not have yet are requested again in a smaller batch, as
+// configured. A failure of such a retry leaves the blocks reported as not found.
+func (h *BlockRewardHandler) payloadBlocksWithReceipts(ctx context.Context, slots []uint64, blockNumberHexes []string) ([]services.BlockWithReceipts, error) {
+	fetched, err := h.executionService.GetBlocksWithReceipts(ctx, blockNumberHexes)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 1; attempt <= h.executionLag.attempts; attempt++ {
+		// Collect the blocks that are still missing, remembering where each one belongs.
+		var lagging []int
+		var laggingNumbers []string
+		for j := range fetched {
+			if errors.Is(fetched[j].Err, services.ErrExecutionBlockNotFound) && h.nearHead(ctx, slots[j]) {
+				lagging = append(lagging, j)
+				laggingNumbers = append(laggingNumbers, blockNumberHexes[j])
+			}
+		}
+		if len(lagging) == 0 {
+			break
+		}
+
+		slog.DebugContext(ctx, "execution blocks not found yet, retrying", "block_numbers", laggingNumbers, "attempt", attempt)
+		if err := h.waitExecutionLag(ctx); err != nil {
+			return nil, err
+		}
+		refetched, err := h.executionService.GetBlocksWithReceipts(ctx, laggingNumbers)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to retry execution blocks", "block_numbers", laggingNumbers, "error", err)
+			break
+		}
+		for k, j := range lagging {
+			fetched[j] = refetched[k]
+		}
+	}
+	return fetched, nil
+}
+
+// retryLagging calls fetch and, while it reports the execution block of a recent slot as not found, calls it again as configured.
+func (h *BlockRewardHandler) retryLagging(ctx context.Context, slot uint64, blockNumberHex string, fetch func() error) error {
+	err := fetch()
+	if !errors.Is(err, services.ErrExecutionBlockNotFound) || !h.nearHead(ctx, slot) {
+		return err
+	}
+	for attempt := 1; attempt <= h.executionLag.attempts && errors.Is(err, services.ErrExecutionBlockNotFound); attempt++ {
+		slog.DebugContext(ctx, "execution block not found yet, retrying", "block_number", blockNumberHex, "attempt", attempt)
+		if err := h.waitExecutionLag(ctx); err != nil {
+			return err
+		}
+		err = fetch()
+	}
+	return err
+}
+
+// nearHead reports whether slot lies within executionLagSlots of the head. It is only consulted once an execution block
+// is not found, and the head slot is usually cached, so it rarely costs an upstream request. If the head cannot be
+// determined, the slot is treated as recent, so that a lagging execution client is still waited for.
+func (h *BlockRewardHandler) nearHead(ctx context.Context, slot uint64) bool {
+	if h.executionLag.attempts == 0 {
+		return false
+	}
+	headSlot, err := h.consensusService.GetHeadSlot(ctx)
+	return err != nil || slot+executionLagSlots >= headSlot
+}
+
+// waitExecutionLag waits the configured delay before a retry. It returns the context's error if ctx is canceled first.
+func (h *BlockRewardHandler) waitExecutionLag(ctx context.Context) error {
+	select {
+	case <-time.After(h.executionLag.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
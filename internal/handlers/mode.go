@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rewardMode selects how the priority fee a transaction paid to the proposer is derived.
+type rewardMode string
+
+const (
+	// modeEffective derives the priority fee from the effective gas price in the transaction's receipt minus the base fee,
+	// i.e. from what the sender was actually charged.
+	modeEffective rewardMode = "effective"
+
+	// modeSigned derives the priority fee from the fee fields the sender signed: min(maxPriorityFeePerGas, maxFeePerGas - baseFee)
+	// for dynamic fee transactions and gasPrice - baseFee otherwise.
+	modeSigned rewardMode = "signed"
+)
+
+// parseModeParam parses the mode query parameter, defaulting to effective.
+// It writes the error response itself and returns false when the mode is not supported.
+func parseModeParam(c *gin.Context) (rewardMode, bool) {
+	switch mode := rewardMode(c.DefaultQuery("mode", string(modeEffective))); mode {
+	case modeEffective, modeSigned:
+		return mode, true
+	}
+	utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, "invalid mode parameter: must be effective or signed", gin.H{"allowed": []rewardMode{modeEffective, modeSigned}})
+	return "", false
+}
+
+// blockRewardInMode returns the proposer reward for a slot that is known not to be in the future, attributed in the given mode.
+// Only rewards in the default effective mode are cached. The returned value may be shared and must not be modified.
+func (h *BlockRewardHandler) blockRewardInMode(ctx context.Context, slot uint64, mode rewardMode) (*models.BlockReward, error) {
+	if mode == modeEffective {
+		return h.blockReward(ctx, slot)
+	}
+	return h.computeBlockReward(ctx, slot, mode)
+}
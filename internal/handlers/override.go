@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers naming, by alias, the upstream endpoint a single request is served from when upstream overrides are enabled,
+// and carrying the credential that allows it.
+const (
+	headerUpstreamConsensus = "X-Upstream-Consensus"
+	headerUpstreamExecution = "X-Upstream-Execution"
+	headerUpstreamToken     = "X-Upstream-Override-Token"
+)
+
+// upstreamOverrides holds the providers a request may select through the override headers, keyed by alias, and the
+// token it must present to do so.
+type upstreamOverrides struct {
+	token     string
+	consensus map[string]ConsensusProvider
+	execution map[string]ExecutionProvider
+}
+
+// WithUpstreamOverrides lets requests presenting token in the X-Upstream-Override-Token header select the consensus or
+// execution endpoint they are served from through the X-Upstream-Consensus and X-Upstream-Execution headers. The
+// headers name an endpoint by its alias in the given maps, so that clients never handle the endpoint URLs and the
+// provider credentials they may embed. Without this option the headers are rejected.
+func WithUpstreamOverrides(token string, consensus map[string]ConsensusProvider, execution map[string]ExecutionProvider) HandlerOption {
+	return func(h *BlockRewardHandler) {
+		h.overrides = &upstreamOverrides{token: token, consensus: consensus, execution: execution}
+	}
+}
+
+// authorized reports whether token is the configured override token, comparing in constant time.
+func (o *upstreamOverrides) authorized(token string) bool {
+	return o.token != "" && subtle.ConstantTimeCompare([]byte(o.token), []byte(token)) == 1
+}
+
+// route adapts a handler method to a gin handler that serves the request from the upstream endpoints selected by the
+// override headers, if any. Overridden requests bypass the reward cache and the head event stream, which reflect the
+// configured endpoints.
+func (h *BlockRewardHandler) route(handle func(*BlockRewardHandler, *gin.Context)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		consensusAlias := c.GetHeader(headerUpstreamConsensus)
+		executionAlias := c.GetHeader(headerUpstreamExecution)
+		if consensusAlias == "" && executionAlias == "" {
+			handle(h, c)
+			return
+		}
+		if h.overrides == nil {
+			utils.RespondError(c, http.StatusForbidden, utils.CodeOverrideDenied, "upstream override is disabled")
+			return
+		}
+		// Check the token before the aliases, so that callers without it cannot learn which aliases exist.
+		if !h.overrides.authorized(c.GetHeader(headerUpstreamToken)) {
+			utils.RespondError(c, http.StatusForbidden, utils.CodeOverrideDenied, "missing or invalid upstream override token")
+			return
+		}
+
+		overridden := *h
+		overridden.rewardCache = nil
+		overridden.headFeed = nil
+		if consensusAlias != "" {
+			provider, ok := h.overrides.consensus[consensusAlias]
+			if !ok {
+				utils.RespondError(c, http.StatusForbidden, utils.CodeOverrideDenied, "unknown consensus override alias")
+				return
+			}
+			overridden.consensusService = provider
+		}
+		if executionAlias != "" {
+			provider, ok := h.overrides.execution[executionAlias]
+			if !ok {
+				utils.RespondError(c, http.StatusForbidden, utils.CodeOverrideDenied, "unknown execution override alias")
+				return
+			}
+			overridden.executionService = provider
+		}
+		slog.InfoContext(c.Request.Context(), "serving request from overridden upstream",
+			"consensus", consensusAlias, "execution", executionAlias)
+		handle(&overridden, c)
+	}
+}
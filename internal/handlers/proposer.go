@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProposerReward handles HTTP requests to retrieve the total reward earned by the proposer of a slot.
+// It combines the execution-layer priority fees with the consensus-layer reward reported by the beacon node.
+// Blocks from before the merge earn consensus-layer rewards only; they are reported with a null execution-layer
+// reward and the PRE_MERGE_SLOT code rather than rejected.
+func (h *BlockRewardHandler) GetProposerReward(c *gin.Context) {
+	// Parse the slot parameter from the request URL.
+	slot, ok := h.resolveSlotParam(c)
+	if !ok {
+		return
+	}
+
+	// Ensure the requested slot is not in the future by comparing it with the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if slot > headSlot {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is in the future")
+		return
+	}
+
+	// Compute the execution-layer reward from the block's priority fees, which blocks from before the merge do not have.
+	elReward, err := h.blockReward(c.Request.Context(), slot)
+	preMerge := errors.Is(err, errPreMergeSlot)
+	if err != nil && !preMerge {
+		respondError(c, err)
+		return
+	}
+
+	// Retrieve the consensus-layer reward for the same block.
+	clReward, err := h.consensusService.GetBlockConsensusReward(c.Request.Context(), slot)
+	if err != nil {
+		if errors.Is(err, services.ErrBlockNotFound) {
+			respondError(c, h.classifyMissingSlot(c.Request.Context(), slot))
+			return
+		}
+		respondError(c, upstreamError("failed to get consensus block reward", err))
+		return
+	}
+	clGwei, ok := new(big.Int).SetString(clReward.Data.Total, 10)
+	if !ok {
+		utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternalError, "invalid consensus block reward")
+		return
+	}
+
+	// Add both rewards up in wei so that no precision is lost before converting the total to gwei.
+	totalWei := big.NewInt(0).Mul(clGwei, big.NewInt(1_000_000_000))
+	var elRewardGwei *string
+	code := utils.CodePreMergeSlot
+	if !preMerge {
+		elWei, ok := new(big.Int).SetString(elReward.RewardWei, 10)
+		if !ok {
+			utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternalError, "invalid reward")
+			return
+		}
+		totalWei.Add(totalWei, elWei)
+		elGwei := formatUnits(elWei, "gwei")
+		elRewardGwei = &elGwei
+		code = ""
+	}
+
+	// Respond with the reward breakdown.
+	c.JSON(http.StatusOK, models.ProposerReward{
+		Code:     code,
+		ELReward: elRewardGwei,
+		CLReward: clReward.Data.Total,
+		Total:    formatUnits(totalWei, "gwei"),
+		CLDetail: models.ConsensusRewardDetails{
+			Attestations:      clReward.Data.Attestations,
+			SyncAggregate:     clReward.Data.SyncAggregate,
+			ProposerSlashings: clReward.Data.ProposerSlashings,
+			AttesterSlashings: clReward.Data.AttesterSlashings,
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+)
+
+// ConsensusProvider is the consensus-layer data the handlers depend on.
+// *services.ConsensusService implements it against a beacon node; other implementations can serve canned data.
+type ConsensusProvider interface {
+	Network() services.NetworkConfig
+	SlotsPerEpoch() uint64
+	SlotToTime(slot uint64) time.Time
+	TimeToSlot(t time.Time) (uint64, error)
+	SyncCommitteePeriod(slot uint64) uint64
+
+	GetHeadSlot(ctx context.Context) (uint64, error)
+	FetchHeadSlot(ctx context.Context) (uint64, error)
+	ResolveSlot(ctx context.Context, alias string) (uint64, error)
+	GetFinalityCheckpoints(ctx context.Context) (*models.FinalityCheckpointsResponse, error)
+	GetBeaconBlock(ctx context.Context, blockID string) (*models.BeaconBlockResponse, error)
+	GetBeaconBlockBySlot(ctx context.Context, slot uint64) (*models.BeaconBlockResponse, error)
+	GetBlockHeader(ctx context.Context, blockID string) (*models.BeaconHeaderResponse, error)
+	GetBlockHeadersBySlot(ctx context.Context, slot uint64) (*models.BeaconHeadersResponse, error)
+	GetBlockConsensusReward(ctx context.Context, slot uint64) (*models.ConsensusBlockRewardsResponse, error)
+	GetAttestationRewards(ctx context.Context, epoch uint64, validators []string) (*models.AttestationRewardsResponse, error)
+	GetSyncCommittee(ctx context.Context, period uint64) ([]string, error)
+	GetSyncCommitteeDuties(ctx context.Context, slot uint64) ([]string, error)
+	GetSyncCommitteeRewards(ctx context.Context, slot uint64) (*models.SyncCommitteeRewardsResponse, error)
+	GetValidatorPubkeys(ctx context.Context, indices []string) (map[string]string, error)
+	GetProposerDuties(ctx context.Context, epoch uint64) ([]models.ProposerDuty, error)
+	GetSpec(ctx context.Context) (services.Spec, error)
+	GetGenesis(ctx context.Context) (*models.GenesisResponse, error)
+}
+
+// ExecutionProvider is the execution-layer data the handlers depend on.
+// *services.ExecutionService implements it against a JSON-RPC node; other implementations can serve canned data.
+type ExecutionProvider interface {
+	GetExecutionBlockByNumber(ctx context.Context, blockNumberHex string) (*models.ExecutionBlockFullResponse, error)
+	GetExecutionBlockWithTransactions(ctx context.Context, blockNumberHex string) (*models.ExecutionBlockFullResponse, error)
+	GetBlockReceipts(ctx context.Context, blockNumberHex string) ([]models.TransactionReceipt, error)
+	GetBlocksWithReceipts(ctx context.Context, blockNumberHexes []string) ([]services.BlockWithReceipts, error)
+	GetBalanceDelta(ctx context.Context, address string, blockNumber uint64) (*big.Int, error)
+	GetBlockNumber(ctx context.Context) (uint64, error)
+	GetChainID(ctx context.Context) (uint64, error)
+}
+
+// RelayProvider is the MEV-Boost relay data the handlers use to attribute blocks to relays and builders.
+// *services.RelayService implements it against the relays' data APIs.
+type RelayProvider interface {
+	GetDeliveredPayload(ctx context.Context, slot uint64) (*services.DeliveredPayload, error)
+}
+
+// The services are the providers used in production.
+var (
+	_ ConsensusProvider = (*services.ConsensusService)(nil)
+	_ ExecutionProvider = (*services.ExecutionService)(nil)
+	_ RelayProvider     = (*services.RelayService)(nil)
+)
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRangeLimit is the number of slots returned per page when the client does not specify a limit.
+const defaultRangeLimit = 50
+
+// maxRangeLimit caps the number of slots returned in a single page.
+const maxRangeLimit = 100
+
+// mimeNDJSON is the media type of newline-delimited JSON responses, which stream one JSON object per line.
+const mimeNDJSON = "application/x-ndjson"
+
+// maxStreamRangeSlots caps the number of slots a single NDJSON stream may cover. It amounts to one week on mainnet.
+const maxStreamRangeSlots = 50400
+
+// streamChunkSlots is the number of slots whose rewards are computed together while streaming. Small chunks get
+// the first lines to the client quickly, while still fetching the execution blocks of several slots per batch call.
+const streamChunkSlots = 2 * batchWorkers
+
+// GetBlockRewardRange handles HTTP requests to retrieve the block rewards for a contiguous slot range.
+// Results are paginated; the response carries a next_cursor until the end of the range is reached.
+// Clients sending "Accept: text/csv" receive the page as CSV, with the next cursor in the X-Next-Cursor header.
+// Clients sending "Accept: application/x-ndjson" receive the whole rest of the range as a stream instead of a page.
+func (h *BlockRewardHandler) GetBlockRewardRange(c *gin.Context) {
+	// Parse the range bounds from the query string.
+	from, err := utils.ParseUint(c.Query("from"))
+	if err != nil {
+		respondInvalidInput(c, "from", err)
+		return
+	}
+	to, err := utils.ParseUint(c.Query("to"))
+	if err != nil {
+		respondInvalidInput(c, "to", err)
+		return
+	}
+	if from > to {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidParameter, "from must not be greater than to")
+		return
+	}
+
+	// Parse the page size, falling back to the default when it is absent.
+	limit := uint64(defaultRangeLimit)
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err = strconv.ParseUint(limitParam, 10, 64)
+		if err != nil || limit == 0 || limit > maxRangeLimit {
+			utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, fmt.Sprintf("limit must be between 1 and %d", maxRangeLimit), gin.H{"max_limit": maxRangeLimit})
+			return
+		}
+	}
+
+	// The cursor is the next slot to return; it must fall within the requested range.
+	start := from
+	if cursor := c.Query("cursor"); cursor != "" {
+		start, err = strconv.ParseUint(cursor, 10, 64)
+		if err != nil || start < from || start > to {
+			utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidParameter, "invalid cursor")
+			return
+		}
+	}
+
+	// Ensure the range does not extend into the future by comparing it with the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if to > headSlot {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is in the future")
+		return
+	}
+
+	// Stream the rest of the range without paging when NDJSON is preferred.
+	format := c.NegotiateFormat(gin.MIMEJSON, mimeCSV, mimeNDJSON)
+	if format == mimeNDJSON {
+		if to-start >= maxStreamRangeSlots {
+			utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, fmt.Sprintf("streamed ranges must not span more than %d slots", maxStreamRangeSlots), gin.H{"max_slots": maxStreamRangeSlots})
+			return
+		}
+		h.streamRange(c, start, to, headSlot)
+		return
+	}
+
+	// Collect the slots of this page.
+	end := to
+	if to-start >= limit {
+		end = start + limit - 1
+	}
+	slots := make([]uint64, 0, end-start+1)
+	for slot := start; slot <= end; slot++ {
+		slots = append(slots, slot)
+	}
+
+	resp := models.RangeRewardResponse{
+		Results: h.blockRewards(c.Request.Context(), slots, headSlot),
+	}
+	if end < to {
+		resp.NextCursor = strconv.FormatUint(end+1, 10)
+	}
+
+	// Respond with the page of results.
+	if format == mimeCSV {
+		if resp.NextCursor != "" {
+			c.Header(nextCursorHeader, resp.NextCursor)
+		}
+		respondRewardsCSV(c, resp.Results)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// streamRange streams the results of the slots from start to end, inclusive, as NDJSON: one JSON object per slot, in
+// slot order, in the format of a batch result. The rewards are computed a chunk at a time and every chunk is flushed
+// to the client as soon as it is complete, so memory stays bounded however long the range. When the client disconnects,
+// the request context is canceled, which aborts the outstanding upstream calls and ends the stream.
+func (h *BlockRewardHandler) streamRange(c *gin.Context, start, end, headSlot uint64) {
+	ctx := c.Request.Context()
+	c.Header("Content-Type", mimeNDJSON)
+	c.Status(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	for chunkStart := start; chunkStart <= end; chunkStart += streamChunkSlots {
+		chunkEnd := end
+		if end-chunkStart >= streamChunkSlots {
+			chunkEnd = chunkStart + streamChunkSlots - 1
+		}
+		slots := make([]uint64, 0, chunkEnd-chunkStart+1)
+		for slot := chunkStart; slot <= chunkEnd; slot++ {
+			slots = append(slots, slot)
+		}
+
+		results := h.blockRewards(ctx, slots, headSlot)
+		if ctx.Err() != nil {
+			return // The client is gone; the results of the canceled chunk are incomplete anyway.
+		}
+		for _, result := range results {
+			if err := enc.Encode(result); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+		if chunkEnd == end {
+			return // Stop before chunkStart wraps around at the end of the slot space.
+		}
+	}
+}
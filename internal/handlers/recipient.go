@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRecipientScanSlots caps the number of slots a single fee recipient query may scan, since the beacon block of
+// every slot is fetched. It amounts to about three hours on mainnet.
+const maxRecipientScanSlots = 1024
+
+// GetRewardsByRecipient handles HTTP requests to retrieve the blocks between from_slot and to_slot, inclusive, whose
+// fee recipient is the given address, together with their summed rewards. The fee recipient of each slot is read from
+// its beacon block first, so execution blocks and receipts are only fetched for the matching blocks.
+// Slots without a block or from before the merge are skipped; slots whose beacon block cannot be fetched are counted
+// in failed_slots, since they may have paid the address.
+func (h *BlockRewardHandler) GetRewardsByRecipient(c *gin.Context) {
+	// Parse the fee recipient address from the request URL.
+	address, err := utils.ParseHexData(c.Param("address"), 20)
+	if err != nil {
+		respondInvalidInput(c, "address", err)
+		return
+	}
+
+	// Parse the slot range from the query string.
+	from, err := utils.ParseUint(c.Query("from_slot"))
+	if err != nil {
+		respondInvalidInput(c, "from_slot", err)
+		return
+	}
+	to, err := utils.ParseUint(c.Query("to_slot"))
+	if err != nil {
+		respondInvalidInput(c, "to_slot", err)
+		return
+	}
+	if from > to {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidParameter, "from_slot must not be greater than to_slot")
+		return
+	}
+	if to-from >= maxRecipientScanSlots {
+		utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, fmt.Sprintf("slot range must not span more than %d slots", maxRecipientScanSlots), gin.H{"max_slots": maxRecipientScanSlots})
+		return
+	}
+
+	// Ensure the range does not extend into the future by comparing it with the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if to > headSlot {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is in the future")
+		return
+	}
+
+	// Read the fee recipient of every slot with the bounded worker pool of the batch endpoint, from the reward cache
+	// where possible and from the beacon block otherwise.
+	slots := make([]uint64, 0, to-from+1)
+	for slot := from; slot <= to; slot++ {
+		slots = append(slots, slot)
+	}
+	recipients := make([]string, len(slots))
+	errs := make([]error, len(slots))
+	forEachSlot(len(slots), func(i int) {
+		if reward, ok := h.cachedReward(c.Request.Context(), slots[i]); ok {
+			recipients[i] = reward.FeeRecipient
+			return
+		}
+		beaconBlock, err := h.fetchBeaconBlock(c.Request.Context(), slots[i])
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		recipients[i] = beaconBlock.Data.Message.Body.ExecutionPayload.FeeRecipient
+	})
+
+	resp := models.RecipientRewards{
+		FeeRecipient: address,
+		FromSlot:     from,
+		ToSlot:       to,
+	}
+	var matching []uint64
+	for i, slot := range slots {
+		var reqErr *requestError
+		switch {
+		case errors.As(errs[i], &reqErr) && (missingSlotStatuses[reqErr] != "" || reqErr == errPreMergeSlot):
+			continue
+		case errs[i] != nil:
+			resp.FailedSlots++
+		case strings.EqualFold(recipients[i], address):
+			matching = append(matching, slot)
+		}
+	}
+
+	// Compute the rewards of the matching blocks and sum them up.
+	resp.Blocks = h.blockRewards(c.Request.Context(), matching, headSlot)
+	totalReward := big.NewInt(0)
+	for _, result := range resp.Blocks {
+		if result.Error != "" || result.Status == "missed" || result.Status == "orphaned" || result.Status == "pre_merge" {
+			resp.FailedSlots++
+			continue
+		}
+		rewardWei, ok := new(big.Int).SetString(result.RewardWei, 10)
+		if !ok {
+			utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternalError, "invalid reward")
+			return
+		}
+		resp.MatchedBlocks++
+		totalReward.Add(totalReward, rewardWei)
+	}
+	resp.Reward = formatUnits(totalReward, "gwei")
+	resp.RewardWei = totalReward.String()
+
+	// Respond with the sum and the matching blocks.
+	c.JSON(http.StatusOK, resp)
+}
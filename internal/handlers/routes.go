@@ -0,0 +1,68 @@
+package handlers
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers the reward, slot and sync committee endpoints served by the handler.
+// Keeping the routes here rather than in main lets any router, such as one in front of mock upstreams, serve the same API.
+// Every route honors the upstream override headers; see WithUpstreamOverrides.
+func (h *BlockRewardHandler) RegisterRoutes(r gin.IRoutes) {
+	// Define an HTTP GET endpoint for retrieving block rewards by slot.
+	r.GET("/blockreward/:slot", h.route((*BlockRewardHandler).GetBlockReward))
+
+	// Define an HTTP GET endpoint for retrieving the per-transaction breakdown of the block reward by slot.
+	r.GET("/blockreward/:slot/transactions", h.route((*BlockRewardHandler).GetBlockRewardTransactions))
+
+	// Define an HTTP GET endpoint for retrieving the head slot together with the justified and finalized checkpoints.
+	r.GET("/checkpoint", h.route((*BlockRewardHandler).GetCheckpoint))
+
+	// Define an HTTP GET endpoint for retrieving the parameters of the network the upstream nodes serve.
+	r.GET("/chaininfo", h.route((*BlockRewardHandler).GetChainInfo))
+
+	// Define an HTTP GET endpoint for retrieving the slot active at a Unix timestamp.
+	r.GET("/slotattime", h.route((*BlockRewardHandler).GetSlotAtTime))
+
+	// Define an HTTP GET endpoint for retrieving the consensus and execution-layer metadata of a slot.
+	r.GET("/slotinfo/:slot", h.route((*BlockRewardHandler).GetSlotInfo))
+
+	// Define an HTTP GET endpoint for retrieving block rewards by execution block number.
+	r.GET("/blockreward/byblock/:number", h.route((*BlockRewardHandler).GetBlockRewardByBlockNumber))
+
+	// Define an HTTP GET endpoint for retrieving block rewards by beacon block root.
+	r.GET("/blockreward/byroot/:root", h.route((*BlockRewardHandler).GetBlockRewardByRoot))
+
+	// Define an HTTP POST endpoint for retrieving block rewards for several slots at once.
+	r.POST("/blockreward/batch", h.route((*BlockRewardHandler).GetBlockRewardBatch))
+
+	// Define an HTTP GET endpoint for retrieving block rewards for a paginated slot range.
+	r.GET("/blockreward/range", h.route((*BlockRewardHandler).GetBlockRewardRange))
+
+	// Define an HTTP GET endpoint for retrieving the aggregated block rewards of all slots in an epoch.
+	r.GET("/epochreward/:epoch", h.route((*BlockRewardHandler).GetEpochReward))
+
+	// Define an HTTP GET endpoint for retrieving summary statistics of the block rewards of an epoch.
+	r.GET("/epochreward/:epoch/stats", h.route((*BlockRewardHandler).GetEpochRewardStats))
+
+	// Define an HTTP GET endpoint for retrieving the combined execution and consensus-layer proposer reward by slot.
+	r.GET("/proposerreward/:slot", h.route((*BlockRewardHandler).GetProposerReward))
+
+	// Define an HTTP GET endpoint for retrieving the aggregated block rewards of a validator's proposals over an epoch range.
+	r.GET("/validator/:index/blockrewards", h.route((*BlockRewardHandler).GetValidatorBlockRewards))
+
+	// Define an HTTP GET endpoint for retrieving the blocks of a slot range paid to a fee recipient, with their summed rewards.
+	r.GET("/rewards/byrecipient/:address", h.route((*BlockRewardHandler).GetRewardsByRecipient))
+
+	// Define an HTTP GET endpoint for retrieving per-validator attestation rewards by epoch.
+	r.GET("/attestationrewards/:epoch", h.route((*BlockRewardHandler).GetAttestationRewards))
+
+	// Define an HTTP GET endpoint for retrieving sync committee duties by slot.
+	r.GET("/syncduties/:slot", h.route((*BlockRewardHandler).GetSyncDuties))
+
+	// Define an HTTP GET endpoint for retrieving the sync committee of a whole sync committee period.
+	r.GET("/syncduties/period/:period", h.route((*BlockRewardHandler).GetSyncDutiesByPeriod))
+
+	// Define an HTTP GET endpoint for retrieving the sync committee rewards paid out in a block by slot.
+	r.GET("/syncrewards/:slot", h.route((*BlockRewardHandler).GetSyncRewards))
+
+	// Define a WebSocket endpoint streaming the block reward of every new head slot.
+	r.GET("/ws/blockrewards", h.route((*BlockRewardHandler).StreamBlockRewards))
+}
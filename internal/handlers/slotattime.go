@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSlotAtTime handles HTTP requests to retrieve the slot active at a Unix timestamp, given by the ts query parameter.
+// The slot is derived from the network's genesis time, so timestamps before genesis or beyond the head slot are rejected.
+func (h *BlockRewardHandler) GetSlotAtTime(c *gin.Context) {
+	// Parse the timestamp from the query string.
+	ts, err := strconv.ParseInt(c.Query("ts"), 10, 64)
+	if err != nil {
+		respondInvalidInput(c, "ts", &utils.InputError{Value: c.Query("ts"), Reason: "not a Unix timestamp in seconds"})
+		return
+	}
+
+	// Convert the timestamp to the slot active at that time.
+	slot, err := h.consensusService.TimeToSlot(time.Unix(ts, 0))
+	if err != nil {
+		genesis := h.consensusService.Network().Genesis()
+		utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, "timestamp is before genesis", gin.H{"genesis_time": genesis.Unix()})
+		return
+	}
+
+	// Ensure the slot is not in the future by comparing it with the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if slot > headSlot {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "timestamp lies beyond the head slot")
+		return
+	}
+
+	// Respond with the slot and the time it started.
+	c.JSON(http.StatusOK, models.SlotAtTime{
+		Timestamp: ts,
+		Slot:      slot,
+		Epoch:     slot / h.consensusService.SlotsPerEpoch(),
+		SlotStart: h.consensusService.SlotToTime(slot).UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSlotInfo handles HTTP requests to retrieve the consensus and execution-layer metadata of a slot.
+// Missed and orphaned slots are valid outcomes here, so they are answered with 200 and marked by their status.
+func (h *BlockRewardHandler) GetSlotInfo(c *gin.Context) {
+	// Parse the slot parameter from the request URL.
+	slot, ok := h.resolveSlotParam(c)
+	if !ok {
+		return
+	}
+
+	// Ensure the requested slot is not in the future by comparing it with the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if slot > headSlot {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is in the future")
+		return
+	}
+
+	info := models.SlotInfo{
+		Slot:      slot,
+		Timestamp: h.consensusService.SlotToTime(slot).UTC().Format(time.RFC3339),
+	}
+
+	// Retrieve the beacon block for the slot, reporting slots without a canonical block by their status.
+	beaconBlock, err := h.consensusService.GetBeaconBlockBySlot(c.Request.Context(), slot)
+	if err != nil {
+		if !errors.Is(err, services.ErrBlockNotFound) {
+			respondError(c, upstreamError("failed to get beacon block", err))
+			return
+		}
+		reqErr := h.classifyMissingSlot(c.Request.Context(), slot)
+		status, ok := missingSlotStatuses[reqErr]
+		if !ok {
+			respondError(c, reqErr)
+			return
+		}
+		info.Status = status
+		info.Missed = true
+		c.JSON(http.StatusOK, info)
+		return
+	}
+
+	// The block itself does not carry its root, so retrieve it from the block's header.
+	header, err := h.consensusService.GetBlockHeader(c.Request.Context(), strconv.FormatUint(slot, 10))
+	if err != nil {
+		respondError(c, upstreamError("failed to get block header", err))
+		return
+	}
+
+	message := beaconBlock.Data.Message
+	info.Status = "proposed"
+	info.ProposerIndex = message.ProposerIndex
+	info.BlockRoot = header.Data.Root
+	info.ParentRoot = message.ParentRoot
+
+	// Blocks before the merge have no execution payload, so their execution fields are left out.
+	if !h.consensusService.Network().IsPreMerge(slot) {
+		payload := message.Body.ExecutionPayload
+		info.ExecutionBlockNumber = payload.BlockNumber
+		info.ExecutionBlockHash = payload.BlockHash
+		info.FeeRecipient = payload.FeeRecipient
+	}
+
+	// Respond with the metadata of the slot.
+	c.JSON(http.StatusOK, info)
+}
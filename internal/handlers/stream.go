@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// headPollInterval is how often a reward stream checks the beacon node for a new head slot.
+const headPollInterval = 2 * time.Second
+
+// streamWriteTimeout bounds how long a reward stream waits for a client to accept a message before dropping it.
+const streamWriteTimeout = 10 * time.Second
+
+// WithAllowedOrigins sets the origins from which browsers may open reward streams, in addition to the API's own origin.
+// An origin of "*" allows every origin.
+func WithAllowedOrigins(origins []string) HandlerOption {
+	return func(h *BlockRewardHandler) {
+		h.allowedOrigins = make(map[string]bool, len(origins))
+		for _, origin := range origins {
+			h.allowedOrigins[strings.TrimSuffix(origin, "/")] = true
+		}
+	}
+}
+
+// WithHeadEvents drives the reward streams with the head slots received from heads, typically the channel returned by
+// ConsensusService.SubscribeHeads, instead of polling the head slot for every stream.
+func WithHeadEvents(heads <-chan uint64) HandlerOption {
+	return func(h *BlockRewardHandler) {
+		h.headFeed = newHeadFeed(heads)
+	}
+}
+
+// headFeed fans the head slots received from a single event subscription out to every reward stream.
+type headFeed struct {
+	mu          sync.Mutex
+	subscribers map[chan uint64]bool
+}
+
+// newHeadFeed starts distributing the slots received from heads until the channel is closed.
+func newHeadFeed(heads <-chan uint64) *headFeed {
+	f := &headFeed{subscribers: make(map[chan uint64]bool)}
+	go func() {
+		for slot := range heads {
+			f.mu.Lock()
+			for subscriber := range f.subscribers {
+				publishLatest(subscriber, slot)
+			}
+			f.mu.Unlock()
+		}
+	}()
+	return f
+}
+
+// subscribe registers a channel receiving the latest head slot until ctx is done.
+func (f *headFeed) subscribe(ctx context.Context, heads chan uint64) {
+	f.mu.Lock()
+	f.subscribers[heads] = true
+	f.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		delete(f.subscribers, heads)
+		f.mu.Unlock()
+	}()
+}
+
+// checkOrigin reports whether a WebSocket handshake comes from an allowed origin.
+// Requests without an Origin header do not come from browsers and are always allowed.
+func (h *BlockRewardHandler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || h.allowedOrigins["*"] || h.allowedOrigins[origin] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// StreamBlockRewards handles WebSocket connections streaming the block reward of every new head slot.
+// New heads are taken from the head event stream when one is configured with WithHeadEvents, and polled otherwise.
+// Each message is a JSON object in the format of the batch endpoint's results. A client that reads slower than slots
+// are produced skips the slots it missed and receives the latest one.
+func (h *BlockRewardHandler) StreamBlockRewards(c *gin.Context) {
+	upgrader := websocket.Upgrader{CheckOrigin: h.checkOrigin}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return // The upgrader has already answered the request with an error.
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// Read from the connection to process control frames and to notice when the client goes away.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Hand new head slots to the writer through a single-slot buffer that always holds the latest head,
+	// so a slow client skips ahead instead of falling further behind.
+	heads := make(chan uint64, 1)
+	if h.headFeed != nil {
+		// Start with the current head rather than waiting up to a slot for the next event.
+		if slot, err := h.consensusService.GetHeadSlot(ctx); err == nil {
+			heads <- slot
+		}
+		h.headFeed.subscribe(ctx, heads)
+	} else {
+		go h.watchHeads(ctx, heads)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case slot := <-heads:
+			reward, err := h.blockReward(ctx, slot)
+			if ctx.Err() != nil {
+				return
+			}
+			result := slotRewardResult(slot, reward, err)
+			conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteJSON(result); err != nil {
+				slog.DebugContext(ctx, "closing reward stream", "error", err)
+				return
+			}
+		}
+	}
+}
+
+// watchHeads polls the head slot until ctx is done and sends every new head to heads.
+// A head the receiver has not picked up yet is replaced by the newer one.
+func (h *BlockRewardHandler) watchHeads(ctx context.Context, heads chan uint64) {
+	ticker := time.NewTicker(headPollInterval)
+	defer ticker.Stop()
+
+	var last uint64
+	for {
+		slot, err := h.consensusService.GetHeadSlot(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to fetch head slot for reward stream", "error", err)
+		} else if slot > last {
+			last = slot
+			publishLatest(heads, slot)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishLatest sends slot to a channel with a buffer of one, replacing any value that has not been received yet.
+func publishLatest(heads chan uint64, slot uint64) {
+	for {
+		select {
+		case heads <- slot:
+			return
+		default:
+		}
+		select {
+		case <-heads: // Drop the stale head to make room for the new one.
+		default:
+		}
+	}
+}
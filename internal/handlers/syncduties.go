@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSyncDutiesByPeriod handles HTTP requests to retrieve the sync committee serving a whole sync committee period.
+// The committee is the same for every slot of the period, so clients need a single request per period.
+func (h *BlockRewardHandler) GetSyncDutiesByPeriod(c *gin.Context) {
+	// Parse the period parameter from the request URL.
+	period, err := utils.ParseUint(c.Param("period"))
+	if err != nil {
+		respondInvalidInput(c, "period", err)
+		return
+	}
+
+	// Ensure the requested period is known. Sync committees are chosen one period in advance,
+	// so the period after the head's is answerable as well.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if period > h.consensusService.SyncCommitteePeriod(headSlot)+1 {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodePeriodInFuture, "requested period is too far in the future")
+		return
+	}
+
+	// Retrieve the sync committee of the period.
+	validators, err := h.consensusService.GetSyncCommittee(c.Request.Context(), period)
+	if err != nil {
+		if errors.Is(err, services.ErrSyncCommitteeNotFound) {
+			utils.RespondError(c, http.StatusNotFound, utils.CodeNotFound, "sync committee not found")
+			return
+		}
+		respondError(c, upstreamError("failed to get sync committee", err))
+		return
+	}
+
+	// Resolve the validator indices to public keys when requested.
+	members, ok := h.syncCommitteeMembers(c, validators)
+	if !ok {
+		return
+	}
+
+	// Respond with the committee and the slots it serves.
+	network := h.consensusService.Network()
+	startEpoch := period * network.SyncPeriodEpochs
+	endEpoch := startEpoch + network.SyncPeriodEpochs - 1
+	slotsPerEpoch := network.SlotsPerEpoch
+	c.JSON(http.StatusOK, gin.H{
+		"period":      period,
+		"start_epoch": startEpoch,
+		"end_epoch":   endEpoch,
+		"start_slot":  startEpoch * slotsPerEpoch,
+		"end_slot":    (endEpoch+1)*slotsPerEpoch - 1,
+		"validators":  members,
+	})
+}
+
+// syncCommitteeMembers returns the sync committee members to respond with: their indices, or index and public key
+// pairs when the pubkeys query parameter is true. It writes the error response itself and returns false on failure.
+func (h *BlockRewardHandler) syncCommitteeMembers(c *gin.Context, validators []string) (any, bool) {
+	if c.Query("pubkeys") != "true" {
+		return validators, true
+	}
+
+	pubkeys, err := h.consensusService.GetValidatorPubkeys(c.Request.Context(), validators)
+	if err != nil {
+		respondError(c, upstreamError("failed to resolve validator public keys", err))
+		return nil, false
+	}
+	keys := make([]models.ValidatorKey, len(validators))
+	for i, index := range validators {
+		keys[i] = models.ValidatorKey{Index: index, Pubkey: pubkeys[index]}
+	}
+	return keys, true
+}
+
+// parsePageParams parses the offset and limit query parameters selecting a page of a list of total entries and
+// returns the bounds of the page. Without them the page covers the whole list. The offset may equal total, which
+// selects an empty page. It writes the error response itself and returns false when either parameter is out of bounds.
+func parsePageParams(c *gin.Context, total int) (int, int, bool) {
+	offset := 0
+	if offsetParam := c.Query("offset"); offsetParam != "" {
+		parsed, err := strconv.Atoi(offsetParam)
+		if err != nil || parsed < 0 || parsed > total {
+			utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, fmt.Sprintf("offset must be between 0 and %d", total), gin.H{"total": total})
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+
+	end := total
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidParameter, "limit must be a positive integer")
+			return 0, 0, false
+		}
+		if limit < total-offset {
+			end = offset + limit
+		}
+	}
+	return offset, end, true
+}
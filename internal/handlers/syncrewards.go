@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/internal/services"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSyncRewards handles HTTP requests to retrieve the sync committee rewards earned in the block at a given slot.
+// Every committee member is listed, so validators that missed their duty show up with a penalty or a zero reward.
+func (h *BlockRewardHandler) GetSyncRewards(c *gin.Context) {
+	// Parse the slot parameter from the request URL.
+	slot, ok := h.resolveSlotParam(c)
+	if !ok {
+		return
+	}
+
+	// Ensure the requested slot is not in the future by comparing it with the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if slot > headSlot {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is in the future")
+		return
+	}
+
+	// Retrieve the committee membership and the rewards paid out in the block.
+	committee, err := h.consensusService.GetSyncCommitteeDuties(c.Request.Context(), slot)
+	if err != nil {
+		if errors.Is(err, services.ErrSyncCommitteeNotFound) {
+			utils.RespondError(c, http.StatusNotFound, utils.CodeNotFound, "sync committee duties not found")
+			return
+		}
+		respondError(c, upstreamError("failed to get sync committee duties", err))
+		return
+	}
+	rewards, err := h.consensusService.GetSyncCommitteeRewards(c.Request.Context(), slot)
+	if err != nil {
+		if errors.Is(err, services.ErrBlockNotFound) {
+			respondError(c, h.classifyMissingSlot(c.Request.Context(), slot))
+			return
+		}
+		respondError(c, upstreamError("failed to get sync committee rewards", err))
+		return
+	}
+
+	// Index the rewards by validator so every committee member can be matched with its reward.
+	rewardByValidator := make(map[string]string, len(rewards.Data))
+	for _, reward := range rewards.Data {
+		rewardByValidator[reward.ValidatorIndex] = reward.Reward
+	}
+
+	// A validator may appear in the committee more than once; the rewards endpoint reports it once.
+	syncRewards := make([]models.SyncReward, 0, len(committee))
+	seen := make(map[string]bool, len(committee))
+	for _, validator := range committee {
+		if seen[validator] {
+			continue
+		}
+		seen[validator] = true
+
+		reward, ok := rewardByValidator[validator]
+		if !ok {
+			reward = "0"
+		}
+		syncRewards = append(syncRewards, models.SyncReward{
+			ValidatorIndex: validator,
+			Reward:         reward,
+			Participated:   reward != "0" && !strings.HasPrefix(reward, "-"),
+		})
+	}
+
+	// Respond with the reward of every committee member.
+	c.JSON(http.StatusOK, gin.H{
+		"slot":    slot,
+		"rewards": syncRewards,
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBlockRewardTransactions handles HTTP requests to retrieve the per-transaction breakdown of a block's proposer reward.
+// The contributions add up to the reward returned by GetBlockReward for the same slot.
+func (h *BlockRewardHandler) GetBlockRewardTransactions(c *gin.Context) {
+	// Parse the slot parameter from the request URL.
+	slot, ok := h.resolveSlotParam(c)
+	if !ok {
+		return
+	}
+
+	// Parse how the transaction rewards are attributed, defaulting to the effective gas price.
+	mode, ok := parseModeParam(c)
+	if !ok {
+		return
+	}
+
+	// Ensure the requested slot is not in the future by comparing it with the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if slot > headSlot {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeSlotInFuture, "requested slot is in the future")
+		return
+	}
+
+	block, err := h.fetchSlotBlock(c.Request.Context(), slot)
+	if err == nil {
+		block, err = h.blockForMode(c.Request.Context(), block, mode)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	transactions := block.transactionRewards(mode)
+	total, err := sumTransactionRewards(transactions)
+	if err != nil {
+		respondError(c, internalError("invalid transaction reward", err))
+		return
+	}
+
+	// Respond with the contribution of every transaction alongside their total.
+	c.JSON(http.StatusOK, models.TransactionRewardsResponse{
+		Slot:         slot,
+		BlockNumber:  block.beacon.Data.Message.Body.ExecutionPayload.BlockNumber,
+		Reward:       formatUnits(total, "gwei"),
+		RewardWei:    total.String(),
+		Mode:         string(mode),
+		Transactions: transactions,
+	})
+}
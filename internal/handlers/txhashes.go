@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"eth-rewards-api/internal/models"
+)
+
+// errHashOnlyTransactions is returned by calculations that need fields of the full transactions of a block that was
+// fetched with transaction hashes only.
+var errHashOnlyTransactions = errors.New("block was fetched with transaction hashes only")
+
+// hashOnly reports whether the execution block was fetched with transaction hashes only, so that its transactions
+// carry just the fields that could be read from their receipts.
+func (b *slotBlock) hashOnly() bool {
+	return hashOnlyTransactions(b.exec)
+}
+
+// hashOnlyTransactions reports whether an execution block was fetched with transaction hashes only.
+func hashOnlyTransactions(execBlock *models.ExecutionBlockFullResponse) bool {
+	return len(execBlock.Result.Transactions) > 0 && execBlock.Result.Transactions[0].HashOnly
+}
+
+// receiptTransactions returns a copy of an execution block fetched with transaction hashes only, whose transactions
+// carry the type, sender, recipient and gas price read from their receipts. The gas price is the effective one, which
+// is exact for legacy and access list transactions; the fee fields of dynamic fee transactions remain unknown.
+// The block itself may be shared and is left unmodified.
+func receiptTransactions(execBlock *models.ExecutionBlockFullResponse, receipts []models.TransactionReceipt) *models.ExecutionBlockFullResponse {
+	filled := *execBlock
+	filled.Result.Transactions = make([]models.ExecutionBlockTx, len(execBlock.Result.Transactions))
+	for i, tx := range execBlock.Result.Transactions {
+		tx.Type = receipts[i].Type
+		tx.From = receipts[i].From
+		tx.To = receipts[i].To
+		tx.GasPrice = receipts[i].EffectiveGasPrice
+		filled.Result.Transactions[i] = tx
+	}
+	return &filled
+}
+
+// blockForMode returns the block with the transaction fields the given mode needs. The signed mode reads the fee fields
+// the senders signed, which receipts lack, so the full transactions of a block fetched with hashes only are retrieved.
+func (h *BlockRewardHandler) blockForMode(ctx context.Context, block *slotBlock, mode rewardMode) (*slotBlock, error) {
+	if mode != modeSigned {
+		return block, nil
+	}
+	return h.withFullTransactions(ctx, block)
+}
+
+// withFullTransactions returns the block with its full transactions, retrieving them if the block was fetched with
+// transaction hashes only. Failures are returned as *requestError values describing the response to send to the client.
+func (h *BlockRewardHandler) withFullTransactions(ctx context.Context, block *slotBlock) (*slotBlock, error) {
+	if !block.hashOnly() {
+		return block, nil
+	}
+	execBlock, err := h.executionService.GetExecutionBlockWithTransactions(ctx, block.exec.Result.Number)
+	if err != nil {
+		return nil, upstreamError("failed to get execution block", err)
+	}
+	return newSlotBlock(block.beacon, execBlock, block.receipts)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unitDecimals maps each supported reward unit to the number of decimal places separating it from wei.
+var unitDecimals = map[string]int{
+	"wei":  0,
+	"gwei": 9,
+	"eth":  18,
+}
+
+// formatUnits formats an amount in wei as an exact decimal string in the given unit ("wei", "gwei" or "eth").
+// Unlike integer division, no fraction of the amount is lost. The unit must be one of the keys of unitDecimals.
+func formatUnits(wei *big.Int, unit string) string {
+	return formatDecimal(wei, unitDecimals[unit])
+}
+
+// formatDecimal formats value / 10^decimals as a decimal string without trailing fractional zeros.
+func formatDecimal(value *big.Int, decimals int) string {
+	if decimals == 0 {
+		return value.String()
+	}
+
+	digits := new(big.Int).Abs(value).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	integer := digits[:len(digits)-decimals]
+	fraction := strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	result := integer
+	if fraction != "" {
+		result += "." + fraction
+	}
+	if value.Sign() < 0 {
+		result = "-" + result
+	}
+	return result
+}
+
+// defaultEthPrecision is the number of decimal places eth amounts are rounded to unless the precision parameter is given.
+const defaultEthPrecision = 9
+
+// rewardUnit is the unit a reward is expressed in and, for eth, the number of decimal places it is rounded to.
+type rewardUnit struct {
+	name      string // "wei", "gwei" or "eth".
+	precision int    // The decimal places of eth amounts; ignored for the other units, which are always exact.
+}
+
+// parseUnitParam parses the unit query parameter, defaulting to gwei, and the precision query parameter,
+// defaulting to defaultEthPrecision. It writes the error response itself and returns false when either is invalid.
+func parseUnitParam(c *gin.Context) (rewardUnit, bool) {
+	unit := rewardUnit{name: c.DefaultQuery("unit", "gwei"), precision: defaultEthPrecision}
+	if _, ok := unitDecimals[unit.name]; !ok {
+		utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, "invalid unit parameter: must be wei, gwei or eth", gin.H{"allowed": []string{"wei", "gwei", "eth"}})
+		return rewardUnit{}, false
+	}
+
+	if precisionParam := c.Query("precision"); precisionParam != "" {
+		precision, err := strconv.Atoi(precisionParam)
+		if err != nil || precision < 0 || precision > unitDecimals["eth"] {
+			utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, fmt.Sprintf("invalid precision parameter: must be between 0 and %d", unitDecimals["eth"]), gin.H{"max_precision": unitDecimals["eth"]})
+			return rewardUnit{}, false
+		}
+		unit.precision = precision
+	}
+	return unit, true
+}
+
+// formatEth formats an amount in wei in eth, rounded to the given number of decimal places with halves rounded away
+// from zero. Exactly that many decimal places are returned.
+func formatEth(wei *big.Int, precision int) string {
+	weiPerEth := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(unitDecimals["eth"])), nil)
+	return new(big.Rat).SetFrac(wei, weiPerEth).FloatString(precision)
+}
+
+// rewardInUnit returns a copy of reward with its reward field expressed in the given unit.
+// The reward itself is left untouched, since computed rewards may be shared through the cache.
+func rewardInUnit(reward *models.BlockReward, unit rewardUnit) (*models.BlockReward, error) {
+	resp := *reward
+	if unit.name != resp.Unit || unit.name == "eth" {
+		rewardWei, ok := new(big.Int).SetString(resp.RewardWei, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid reward %q", resp.RewardWei)
+		}
+		if unit.name == "eth" {
+			resp.Reward = formatEth(rewardWei, unit.precision)
+		} else {
+			resp.Reward = formatUnits(rewardWei, unit.name)
+		}
+		resp.Unit = unit.name
+	}
+	return &resp, nil
+}
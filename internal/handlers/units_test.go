@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		wei  string
+		unit string
+		want string
+	}{
+		{"0", "gwei", "0"},
+		{"1", "wei", "1"},
+		{"1", "gwei", "0.000000001"},
+		{"1", "eth", "0.000000000000000001"},
+		{"999999999", "gwei", "0.999999999"},
+		{"1500000000", "gwei", "1.5"},
+		{"1500000000", "eth", "0.0000000015"},
+		{"1000000000", "gwei", "1"},
+		{"42000000000000", "gwei", "42000"},
+		{"1000000000000000000", "eth", "1"},
+		{"1230000000", "gwei", "1.23"},
+		{"1234567890", "gwei", "1.23456789"},
+		{"-1500000000", "gwei", "-1.5"},
+		{"-1", "gwei", "-0.000000001"},
+	}
+	for _, tt := range tests {
+		wei, ok := new(big.Int).SetString(tt.wei, 10)
+		if !ok {
+			t.Fatalf("invalid test amount %q", tt.wei)
+		}
+		if got := formatUnits(wei, tt.unit); got != tt.want {
+			t.Errorf("formatUnits(%s, %q) = %q, want %q", tt.wei, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestFormatEth(t *testing.T) {
+	tests := []struct {
+		wei       string
+		precision int
+		want      string
+	}{
+		{"1500000000000000000", 2, "1.50"},
+		{"1", 18, "0.000000000000000001"},
+		{"1", 9, "0.000000000"},
+		{"500000000", 9, "0.000000001"}, // Halves round away from zero.
+		{"1000000000000000000", 0, "1"},
+	}
+	for _, tt := range tests {
+		wei, _ := new(big.Int).SetString(tt.wei, 10)
+		if got := formatEth(wei, tt.precision); got != tt.want {
+			t.Errorf("formatEth(%s, %d) = %q, want %q", tt.wei, tt.precision, got, tt.want)
+		}
+	}
+}
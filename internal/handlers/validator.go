@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxValidatorRewardEpochs caps the number of epochs a single validator reward query may span, since the proposer
+// duties of every epoch are fetched separately. It amounts to one day on mainnet.
+const maxValidatorRewardEpochs = 225
+
+// GetValidatorBlockRewards handles HTTP requests to retrieve the block rewards of the slots a validator was scheduled
+// to propose in between from_epoch and to_epoch, inclusive, together with their aggregate.
+// Duties in slots beyond the head are left out, since their blocks have not been proposed yet.
+func (h *BlockRewardHandler) GetValidatorBlockRewards(c *gin.Context) {
+	// Parse the validator index from the request URL.
+	index, err := utils.ParseUint(c.Param("index"))
+	if err != nil {
+		respondInvalidInput(c, "index", err)
+		return
+	}
+
+	// Parse the epoch range from the query string.
+	fromEpoch, err := utils.ParseUint(c.Query("from_epoch"))
+	if err != nil {
+		respondInvalidInput(c, "from_epoch", err)
+		return
+	}
+	toEpoch, err := utils.ParseUint(c.Query("to_epoch"))
+	if err != nil {
+		respondInvalidInput(c, "to_epoch", err)
+		return
+	}
+	if fromEpoch > toEpoch {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidParameter, "from_epoch must not be greater than to_epoch")
+		return
+	}
+	if toEpoch-fromEpoch >= maxValidatorRewardEpochs {
+		utils.RespondErrorWithDetails(c, http.StatusBadRequest, utils.CodeInvalidParameter, fmt.Sprintf("epoch range must not span more than %d epochs", maxValidatorRewardEpochs), gin.H{"max_epochs": maxValidatorRewardEpochs})
+		return
+	}
+
+	// Ensure the range does not extend into the future by comparing it with the epoch of the current head slot.
+	headSlot, err := h.consensusService.GetHeadSlot(c.Request.Context())
+	if err != nil {
+		respondError(c, upstreamError("failed to fetch head slot", err))
+		return
+	}
+	if toEpoch > headSlot/h.consensusService.SlotsPerEpoch() {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeEpochInFuture, "requested epoch is in the future")
+		return
+	}
+
+	// Retrieve the proposer duties of every epoch in the range with the bounded worker pool of the batch endpoint.
+	epochs := make([]uint64, 0, toEpoch-fromEpoch+1)
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		epochs = append(epochs, epoch)
+	}
+	epochSlots := make([][]uint64, len(epochs))
+	errs := make([]error, len(epochs))
+	forEachSlot(len(epochs), func(i int) {
+		epochSlots[i], errs[i] = h.proposedSlots(c.Request.Context(), epochs[i], index, headSlot)
+	})
+	var slots []uint64
+	for i := range epochs {
+		if errs[i] != nil {
+			respondError(c, errs[i])
+			return
+		}
+		slots = append(slots, epochSlots[i]...)
+	}
+
+	// Compute the rewards of the validator's slots and aggregate those of its proposed blocks.
+	results := h.blockRewards(c.Request.Context(), slots, headSlot)
+	resp := models.ValidatorBlockRewards{
+		ValidatorIndex: index,
+		FromEpoch:      fromEpoch,
+		ToEpoch:        toEpoch,
+		Slots:          results,
+	}
+	totalReward := big.NewInt(0)
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			resp.FailedSlots++
+		case result.Status == "pre_merge": // Slots before the merge have no execution-layer reward.
+			resp.PreMergeSlots++
+		case result.Status == "missed":
+			resp.MissedSlots++
+		case result.Status == "orphaned":
+			resp.OrphanedSlots++
+		default:
+			rewardWei, ok := new(big.Int).SetString(result.RewardWei, 10)
+			if !ok {
+				utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternalError, "invalid reward")
+				return
+			}
+			resp.ProposedBlocks++
+			totalReward.Add(totalReward, rewardWei)
+		}
+	}
+	resp.Reward = formatUnits(totalReward, "gwei")
+	resp.RewardWei = totalReward.String()
+
+	// Respond with the aggregate and the per-slot breakdown.
+	c.JSON(http.StatusOK, resp)
+}
+
+// proposedSlots returns the slots of the epoch, up to the head slot, that the validator was scheduled to propose in.
+func (h *BlockRewardHandler) proposedSlots(ctx context.Context, epoch, validatorIndex, headSlot uint64) ([]uint64, error) {
+	duties, err := h.consensusService.GetProposerDuties(ctx, epoch)
+	if err != nil {
+		return nil, upstreamError("failed to fetch proposer duties", err)
+	}
+
+	var slots []uint64
+	for _, duty := range duties {
+		if duty.ValidatorIndex == validatorIndex && duty.Slot <= headSlot {
+			slots = append(slots, duty.Slot)
+		}
+	}
+	return slots, nil
+}
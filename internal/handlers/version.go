@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+
+	"eth-rewards-api/internal/version"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetVersion handles HTTP requests for the build information of the running service.
+func GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, version.Get())
+}
@@ -0,0 +1,54 @@
+// The `logging` package configures the structured JSON logger of the service.
+// It carries the request ID through contexts so that every log line written while serving a request can be correlated.
+
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// requestIDKey is the context key the request ID is stored under.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or an empty string when there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// New returns a logger writing JSON records at or above the given level to w.
+// Records logged with a context carrying a request ID are annotated with a "request_id" attribute.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	return slog.New(contextHandler{handler})
+}
+
+// contextHandler wraps a slog.Handler, adding the request ID of the record's context to every record.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds the request ID carried by ctx, if any, before passing the record on.
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler that keeps adding request IDs on top of the given attributes.
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler that keeps adding request IDs within the given group.
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
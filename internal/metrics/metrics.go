@@ -0,0 +1,90 @@
+// The `metrics` package defines the Prometheus collectors exposed by the service on /metrics.
+// It covers handler latency, upstream call outcomes, and the effectiveness of the reward cache.
+
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the names of all metrics exported by the service.
+const namespace = "eth_rewards_api"
+
+var (
+	// requestDuration records the latency of every HTTP request, labeled by route, method and status code.
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests handled by the API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// upstreamRequests counts every attempt to reach an upstream endpoint, labeled by service and outcome.
+	upstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Requests sent to the consensus and execution endpoints, by outcome.",
+	}, []string{"service", "outcome"})
+)
+
+// Register registers the service's collectors with the default Prometheus registry.
+// It is idempotent, so servers built more than once in the same process (e.g. in tests) can call it again.
+func Register() {
+	register(requestDuration)
+	register(upstreamRequests)
+}
+
+// RegisterRewardCache exposes the hit and miss counts reported by stats as counters.
+func RegisterRewardCache(stats func() (hits, misses uint64)) {
+	register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reward_cache_hits_total",
+		Help:      "Block reward lookups served from the cache.",
+	}, func() float64 {
+		hits, _ := stats()
+		return float64(hits)
+	}))
+	register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reward_cache_misses_total",
+		Help:      "Block reward lookups not found in the cache.",
+	}, func() float64 {
+		_, misses := stats()
+		return float64(misses)
+	}))
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveRequest records the latency of a handled HTTP request.
+func ObserveRequest(route, method string, status int, seconds float64) {
+	requestDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(seconds)
+}
+
+// ObserveUpstream records the outcome of a single request sent to an upstream endpoint.
+// The outcome is "error" for requests that failed without a response, and the status class (2xx, 4xx, 5xx) otherwise.
+func ObserveUpstream(service string, resp *http.Response, err error) {
+	outcome := "error"
+	if err == nil {
+		outcome = strconv.Itoa(resp.StatusCode/100) + "xx"
+	}
+	upstreamRequests.WithLabelValues(service, outcome).Inc()
+}
+
+// register registers a collector, ignoring collectors that have already been registered.
+func register(c prometheus.Collector) {
+	if err := prometheus.Register(c); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			panic(err)
+		}
+	}
+}
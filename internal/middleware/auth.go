@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader is the header clients authenticate with.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth returns middleware that rejects requests without a valid X-API-Key header with 401 Unauthorized.
+// Requests for the exempt paths, such as health probes and metrics scrapes, are always let through.
+// When no keys are configured, authentication is disabled and every request is let through.
+func APIKeyAuth(keys []string, exempt ...string) gin.HandlerFunc {
+	exemptPaths := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if len(keys) == 0 || exemptPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			utils.AbortWithError(c, http.StatusUnauthorized, utils.CodeUnauthorized, "missing API key")
+			return
+		}
+		if !validAPIKey(keys, key) {
+			utils.AbortWithError(c, http.StatusUnauthorized, utils.CodeUnauthorized, "invalid API key")
+			return
+		}
+		c.Next()
+	}
+}
+
+// validAPIKey reports whether key is one of the configured keys.
+// Every key is compared in constant time so that response timing does not leak how much of a key matched.
+func validAPIKey(keys []string, key string) bool {
+	valid := false
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsMaxAge is how long, in seconds, browsers may cache the result of a preflight request.
+const corsMaxAge = "600"
+
+// CORS returns middleware that allows browsers to call the API from the given origins.
+// An origin of "*" allows every origin. Preflight OPTIONS requests from allowed origins are answered with 204 No Content.
+// When no origins are configured, no CORS headers are set and browsers keep enforcing the same-origin policy.
+func CORS(origins []string) gin.HandlerFunc {
+	allowAll := false
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" || !(allowAll || allowed[origin]) {
+			c.Next()
+			return
+		}
+
+		// Responses differ per origin, so caches must key them by it.
+		c.Writer.Header().Add("Vary", "Origin")
+		if allowAll {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		c.Header("Access-Control-Expose-Headers", "Retry-After, ETag, X-Next-Cursor, "+RequestIDHeader)
+
+		// Answer preflight requests directly, before authentication and rate limiting, since browsers send them without credentials.
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Content-Type, If-None-Match, "+APIKeyHeader+", "+RequestIDHeader)
+			c.Header("Access-Control-Max-Age", corsMaxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
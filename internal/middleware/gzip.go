@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Gzip returns middleware that gzip-compresses responses of at least minSize bytes for clients accepting gzip.
+// Only textual responses such as JSON are compressed, and responses that already carry a Content-Encoding are left alone.
+// Responses are buffered until the handler returns; a handler that flushes its writer streams the rest uncompressed.
+// The buffered body of a handler that panics is dropped, leaving the response to Recovery.
+func Gzip(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		// Protocol upgrades such as WebSocket handshakes take over the connection and must not be buffered.
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		// Hand the client's writer back even when a later handler panics, so that Recovery, which runs outside this
+		// middleware, writes its error response to the client rather than into the discarded buffer.
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+		c.Next()
+		writer.finish(minSize)
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value accepts the gzip encoding.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.TrimSpace(coding)
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		// Encodings listed with a zero quality value are explicitly refused.
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			return err == nil && q > 0
+		}
+		return true
+	}
+	return false
+}
+
+// compressible reports whether a response with the given content type benefits from compression.
+func compressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	switch {
+	case mediaType == "text/event-stream":
+		return false // Streams are flushed event by event and must not be held back.
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		mediaType == "application/x-ndjson",
+		strings.HasSuffix(mediaType, "+json"):
+		return true
+	}
+	return false
+}
+
+// gzipWriter buffers the response body so the decision to compress can be made on its final size.
+type gzipWriter struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	passthrough bool // Set once the handler flushed; the remaining body is written as-is.
+}
+
+// Write buffers b, or writes it through when the response is being streamed.
+func (w *gzipWriter) Write(b []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+// WriteString buffers s, or writes it through when the response is being streamed.
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.buf.WriteString(s)
+}
+
+// Flush switches the writer to streaming: the buffered body and everything written afterwards is sent uncompressed.
+func (w *gzipWriter) Flush() {
+	if !w.passthrough {
+		w.passthrough = true
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// finish writes the buffered body, compressing it when it is large enough and of a compressible type.
+func (w *gzipWriter) finish(minSize int) {
+	if w.passthrough {
+		return
+	}
+	if w.buf.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+
+	header := w.Header()
+	if w.buf.Len() < minSize || header.Get("Content-Encoding") != "" || !compressible(header.Get("Content-Type")) {
+		w.ResponseWriter.Write(w.buf.Bytes())
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length") // The length of the compressed body differs from any length set by the handler.
+	gz := gzip.NewWriter(w.ResponseWriter)
+	gz.Write(w.buf.Bytes())
+	gz.Close()
+}
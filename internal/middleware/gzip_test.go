@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGzipPanicRecovered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recovery(), Gzip(0))
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	r.GET("/partial", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"reward": strings.Repeat("1", 2048)})
+		panic("boom after writing")
+	})
+
+	for _, path := range []string{"/panic", "/partial"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+			}
+			if encoding := w.Header().Get("Content-Encoding"); encoding != "" {
+				t.Errorf("Content-Encoding = %q, want none", encoding)
+			}
+			var body utils.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid error body %q: %v", w.Body.String(), err)
+			}
+			if body.Code != utils.CodeInternalError {
+				t.Errorf("code = %q, want %q", body.Code, utils.CodeInternalError)
+			}
+		})
+	}
+}
+
+func TestGzipCompressesLargeResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Gzip(1024))
+	r.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"reward": "1"})
+	})
+	r.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"reward": strings.Repeat("1", 2048)})
+	})
+
+	tests := []struct {
+		path     string
+		encoding string
+	}{
+		{"/small", ""},
+		{"/large", "gzip"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("%s: Content-Encoding = %q, want %q", tt.path, got, tt.encoding)
+		}
+		if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("%s: Vary = %q, want %q", tt.path, got, "Accept-Encoding")
+		}
+	}
+}
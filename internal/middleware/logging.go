@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"eth-rewards-api/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the header a request ID is propagated in, both from clients and back to them.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the length of client-supplied request IDs so they cannot bloat the logs.
+const maxRequestIDLength = 128
+
+// RequestID returns middleware that assigns every request an ID and injects it into the request context.
+// An ID supplied by the client in the X-Request-ID header is reused; otherwise a random one is generated.
+// The ID is echoed in the X-Request-ID response header.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// newRequestID generates a random 128-bit request ID in hexadecimal form.
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Logger returns middleware that writes a structured log record for every request once it has been served.
+// Server errors are logged at error level and client errors at warning level.
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		slog.Log(c.Request.Context(), level, "request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds())/1000,
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
@@ -0,0 +1,26 @@
+// The `middleware` package provides Gin middleware shared by all routes of the API.
+
+package middleware
+
+import (
+	"time"
+
+	"eth-rewards-api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics returns middleware that records the latency of every request, labeled by its route pattern and status code.
+// Requests that match no route are grouped under "unmatched" to keep the label set bounded.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveRequest(route, c.Request.Method, c.Writer.Status(), time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimiterSweepInterval is how often buckets of idle clients are dropped to bound the memory used by the limiter.
+const rateLimiterSweepInterval = time.Minute
+
+// RateLimit returns middleware that limits every client to rps requests per second with bursts of up to burst requests.
+// Clients are identified by their API key when keyByAPIKey is set and a key is sent, and by their IP address otherwise.
+// Requests over the limit are rejected with 429 Too Many Requests and a Retry-After header.
+// Requests for the exempt paths are never limited. A non-positive rps disables rate limiting.
+func RateLimit(rps float64, burst int, keyByAPIKey bool, exempt ...string) gin.HandlerFunc {
+	if rps <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	exemptPaths := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+	limiter := newRateLimiter(rps, float64(burst))
+
+	return func(c *gin.Context) {
+		if exemptPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if key := c.GetHeader(APIKeyHeader); keyByAPIKey && key != "" {
+			client = "key:" + key
+		}
+
+		allowed, wait := limiter.allow(client, time.Now())
+		if !allowed {
+			// Round the wait up to whole seconds, since Retry-After cannot express fractions.
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			utils.AbortWithError(c, http.StatusTooManyRequests, utils.CodeRateLimited, "rate limit exceeded")
+			return
+		}
+		c.Next()
+	}
+}
+
+// tokenBucket holds the tokens left to a single client and when they were last refilled.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket per client, refilled at a fixed rate up to the burst size.
+type rateLimiter struct {
+	rps   float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRateLimiter returns a rate limiter refilling rps tokens per second into buckets holding up to burst tokens.
+func newRateLimiter(rps, burst float64) *rateLimiter {
+	return &rateLimiter{
+		rps:       rps,
+		burst:     burst,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow takes a token from the client's bucket, reporting whether one was available.
+// When none was, it also returns how long the client has to wait for the next token.
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = bucket
+	}
+
+	// Refill the tokens earned since the last request, up to the burst size.
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rps)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - bucket.tokens) / l.rps * float64(time.Second))
+}
+
+// sweep drops the buckets that have refilled completely, as they behave exactly like new ones.
+// It runs at most once per sweep interval. The caller must hold l.mu.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for client, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rps >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
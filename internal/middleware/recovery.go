@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"eth-rewards-api/internal/logging"
+	"eth-rewards-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery returns middleware that recovers from panics in later handlers and answers with a JSON 500 error,
+// so clients get the same error envelope as for every other failure instead of gin's plain-text response.
+// The panic and its stack trace are logged with the request ID, which is also returned in the error details.
+// It must run after RequestID so that the ID is available.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// Let the server abort the connection silently, as it does for handlers that panic with ErrAbortHandler.
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			requestID := logging.RequestID(c.Request.Context())
+			slog.ErrorContext(c.Request.Context(), "panic while serving request",
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"panic", fmt.Sprint(recovered),
+				"stack", string(debug.Stack()),
+			)
+
+			// A response that has already been started cannot be replaced; just stop the remaining handlers.
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, utils.ErrorResponse{
+				Code:    utils.CodeInternalError,
+				Message: "internal server error",
+				Details: gin.H{"request_id": requestID},
+			})
+		}()
+		c.Next()
+	}
+}
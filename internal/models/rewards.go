@@ -0,0 +1,267 @@
+// This file defines the data structures returned by the API's own endpoints.
+// They are serialized directly into the JSON responses sent to clients.
+
+package models
+
+import "encoding/json"
+
+// BlockReward represents the computed proposer reward for a single slot.
+type BlockReward struct {
+	Status           string                 `json:"status"`                        // "relay" or "vanilla" for proposed blocks, "missed" when no block was proposed, "orphaned" when it was reorged out, "pre_merge" before the merge.
+	Builder          string                 `json:"builder,omitempty"`             // The name of the builder or relay recognized from the block's extra data.
+	Reward           string                 `json:"reward,omitempty"`              // The priority-fee reward earned by the proposer, in the requested unit (gwei by default).
+	Unit             string                 `json:"unit,omitempty"`                // The unit of the reward field: "wei", "gwei" or "eth".
+	RewardWei        string                 `json:"reward_wei,omitempty"`          // The exact priority-fee reward in wei.
+	Mode             string                 `json:"mode,omitempty"`                // How the transaction rewards were attributed: "effective" or "signed".
+	BurntFees        string                 `json:"burnt_fees,omitempty"`          // The base fees burnt by the block (base fee per gas * gas used), in gwei.
+	BurntFeesWei     string                 `json:"burnt_fees_wei,omitempty"`      // The exact base fees burnt by the block, in wei.
+	TxCount          *int                   `json:"tx_count,omitempty"`            // The number of transactions in the block.
+	TxTypes          *TransactionTypeCounts `json:"tx_types,omitempty"`            // The number of transactions of each type.
+	GasUsed          string                 `json:"gas_used,omitempty"`            // The gas used by the block's transactions.
+	GasLimit         string                 `json:"gas_limit,omitempty"`           // The gas limit of the block.
+	GasUtilization   string                 `json:"gas_utilization,omitempty"`     // The gas used as a percentage of the gas limit, with up to 2 decimals.
+	BlobGasUsed      string                 `json:"blob_gas_used,omitempty"`       // The blob gas used by the block's blob transactions; omitted before Dencun.
+	BlobFeesBurnt    string                 `json:"blob_fees_burnt,omitempty"`     // The blob base fees burnt by the block, in gwei; never paid to the proposer.
+	BlobFeesBurntWei string                 `json:"blob_fees_burnt_wei,omitempty"` // The exact blob base fees burnt by the block, in wei.
+	Withdrawals      []Withdrawal           `json:"withdrawals,omitempty"`         // The validator withdrawals processed by the block.
+	TotalWithdrawals string                 `json:"total_withdrawals,omitempty"`   // The sum of the withdrawn amounts, in gwei; omitted before Shanghai.
+	MEVPayment       string                 `json:"mev_payment,omitempty"`         // The fee recipient's balance change beyond priority fees and withdrawals, in gwei.
+	MEVPaymentWei    string                 `json:"mev_payment_wei,omitempty"`     // The exact MEV payment, in wei.
+	Relays           []string               `json:"relays,omitempty"`              // The MEV-Boost relays that delivered the block, when relay data is configured.
+	BuilderPubkey    string                 `json:"builder_pubkey,omitempty"`      // The public key of the builder, as reported by the relays.
+	MEVValue         string                 `json:"mev_value,omitempty"`           // The value the builder paid the proposer, as reported by the relays, in gwei.
+	MEVValueWei      string                 `json:"mev_value_wei,omitempty"`       // The exact MEV value, in wei.
+	MEVValuePaidWei  string                 `json:"mev_value_paid_wei,omitempty"`  // The value the block actually paid the proposer, in wei; omitted when it cannot be measured.
+	MEVValueMismatch bool                   `json:"mev_value_mismatch,omitempty"`  // Whether the value paid differs from the value reported by the relays.
+	PriorityFeeTotal string                 `json:"priority_fee_total,omitempty"`  // The exact sum of the priority fees of the block's transactions, in gwei, whatever the requested unit.
+	BlockValueTotal  string                 `json:"block_value_total,omitempty"`   // The exact total value the block paid the proposer, in gwei; omitted without relay data or a measured MEV payment.
+	BlockValueSource string                 `json:"block_value_source,omitempty"`  // "priority_fees" when the block value is its priority fees, "builder_payment" when a builder paid it.
+	BlockNumber      string                 `json:"block_number,omitempty"`        // The number of the execution block included in the slot.
+	BlockHash        string                 `json:"block_hash,omitempty"`          // The hash of the execution block.
+	ParentHash       string                 `json:"parent_hash,omitempty"`         // The hash of the execution block's parent.
+	FeeRecipient     string                 `json:"fee_recipient,omitempty"`       // The address that received the block's priority fees.
+	RecipientMatches *bool                  `json:"recipient_matches,omitempty"`   // Whether the fee recipient is one of the expected recipients; included on request.
+	ProposerIndex    string                 `json:"proposer_index,omitempty"`      // The index of the validator that proposed the block.
+	ProposerPubkey   string                 `json:"proposer_pubkey,omitempty"`     // The public key of the proposer, included on request.
+	Timestamp        string                 `json:"timestamp,omitempty"`           // The start time of the slot, in ISO-8601 format.
+	Epoch            *uint64                `json:"epoch,omitempty"`               // The epoch containing the slot; included by the single-slot endpoint.
+	SlotInEpoch      *uint64                `json:"slot_in_epoch,omitempty"`       // The position of the slot within its epoch, from 0.
+
+	Partial   bool   `json:"-"` // Whether a best-effort lookup failed and left fields out; partial rewards are neither cached nor served as immutable.
+	StateRoot string `json:"-"` // The state root of the beacon block the reward was computed from, identifying the block to the reward cache.
+}
+
+// SlotInfo represents the combined consensus and execution-layer metadata of a slot.
+// Slots without a canonical block only carry the slot, its status and its timestamp.
+type SlotInfo struct {
+	Slot                 uint64 `json:"slot"`                             // The slot number.
+	Status               string `json:"status"`                           // "proposed", "missed" when no block was proposed, or "orphaned" when it was reorged out.
+	Missed               bool   `json:"missed"`                           // Whether the slot has no canonical block.
+	ProposerIndex        string `json:"proposer_index,omitempty"`         // The index of the validator that proposed the block.
+	BlockRoot            string `json:"block_root,omitempty"`             // The root of the beacon block.
+	ParentRoot           string `json:"parent_root,omitempty"`            // The root of the parent beacon block.
+	ExecutionBlockNumber string `json:"execution_block_number,omitempty"` // The number of the execution block; omitted before the merge.
+	ExecutionBlockHash   string `json:"execution_block_hash,omitempty"`   // The hash of the execution block; omitted before the merge.
+	FeeRecipient         string `json:"fee_recipient,omitempty"`          // The address that received the block's priority fees; omitted before the merge.
+	Timestamp            string `json:"timestamp"`                        // The start time of the slot, in ISO-8601 format.
+}
+
+// SlotAtTime represents the slot active at a Unix timestamp.
+type SlotAtTime struct {
+	Timestamp int64  `json:"timestamp"`  // The requested Unix timestamp, in seconds.
+	Slot      uint64 `json:"slot"`       // The slot active at the timestamp.
+	Epoch     uint64 `json:"epoch"`      // The epoch containing the slot.
+	SlotStart string `json:"slot_start"` // The start time of the slot, in ISO-8601 format.
+}
+
+// ChainInfo represents the parameters of the network the upstream nodes serve, as reported by the nodes themselves.
+type ChainInfo struct {
+	Network                      string `json:"network"`                          // The name of the network the service is configured for.
+	ChainID                      uint64 `json:"chain_id"`                         // The chain ID reported by the execution client.
+	GenesisTime                  uint64 `json:"genesis_time"`                     // The Unix time of the beacon chain genesis.
+	GenesisValidatorsRoot        string `json:"genesis_validators_root"`          // The root of the genesis validator registry, which identifies the chain.
+	SecondsPerSlot               uint64 `json:"seconds_per_slot"`                 // The duration of a slot in seconds.
+	SlotsPerEpoch                uint64 `json:"slots_per_epoch"`                  // The number of slots in an epoch.
+	EpochsPerSyncCommitteePeriod uint64 `json:"epochs_per_sync_committee_period"` // The number of epochs a sync committee serves.
+}
+
+// ChainCheckpoint represents a point of the beacon chain by its slot and epoch, and for checkpoints its block root.
+type ChainCheckpoint struct {
+	Slot  uint64 `json:"slot"`           // The slot; for finality checkpoints the first slot of the checkpoint epoch.
+	Epoch uint64 `json:"epoch"`          // The epoch containing the slot.
+	Root  string `json:"root,omitempty"` // The block root of the checkpoint; omitted for the head.
+}
+
+// CheckpointResponse represents the head of the beacon chain together with its justified and finalized checkpoints.
+type CheckpointResponse struct {
+	Head      ChainCheckpoint `json:"head"`      // The current head slot.
+	Justified ChainCheckpoint `json:"justified"` // The current justified checkpoint.
+	Finalized ChainCheckpoint `json:"finalized"` // The latest finalized checkpoint; slots at or below it can no longer reorg.
+}
+
+// TransactionTypeCounts represents the composition of a block by transaction type.
+type TransactionTypeCounts struct {
+	Legacy     int `json:"legacy"`      // Type 0 transactions.
+	AccessList int `json:"access_list"` // Type 1 (EIP-2930) transactions.
+	DynamicFee int `json:"dynamic_fee"` // Type 2 (EIP-1559) transactions.
+	Blob       int `json:"blob"`        // Type 3 (EIP-4844) transactions.
+	Other      int `json:"other"`       // Transactions of any type not listed above.
+}
+
+// TransactionReward represents the priority fee a single transaction paid to the block proposer.
+type TransactionReward struct {
+	Hash              string `json:"hash"`                 // The hash of the transaction.
+	PriorityFeePerGas string `json:"priority_fee_per_gas"` // The effective priority fee per unit of gas, in wei.
+	GasUsed           string `json:"gas_used"`             // The gas consumed by the transaction.
+	Reward            string `json:"reward"`               // The transaction's contribution to the proposer reward, in gwei.
+	RewardWei         string `json:"reward_wei"`           // The exact contribution to the proposer reward, in wei.
+}
+
+// TransactionRewardsResponse represents the per-transaction breakdown of a block's proposer reward.
+type TransactionRewardsResponse struct {
+	Slot         uint64              `json:"slot"`         // The slot of the block.
+	BlockNumber  string              `json:"block_number"` // The number of the execution block.
+	Reward       string              `json:"reward"`       // The total proposer reward, in gwei; equal to the reward of /blockreward/:slot.
+	RewardWei    string              `json:"reward_wei"`   // The exact total proposer reward, in wei.
+	Mode         string              `json:"mode"`         // How the transaction rewards were attributed: "effective" or "signed".
+	Transactions []TransactionReward `json:"transactions"` // The contribution of each transaction, in block order.
+}
+
+// SlotRewardResult represents the outcome of a reward lookup for one slot of a multi-slot request.
+// Either the embedded BlockReward or the Error field is set.
+type SlotRewardResult struct {
+	Slot         uint64 `json:"slot"` // The slot the result belongs to.
+	*BlockReward        // The computed reward, when the lookup succeeded.
+	Error        string `json:"error,omitempty"` // The reason the lookup failed, if it did.
+	Code         string `json:"code,omitempty"`  // The machine-readable error code of the failure, or PRE_MERGE_SLOT for a slot before the merge.
+}
+
+// MarshalJSON encodes the result. The result of a slot before the merge is encoded as a PreMergeBlockReward,
+// whose reward is null rather than left out.
+func (r SlotRewardResult) MarshalJSON() ([]byte, error) {
+	if r.BlockReward != nil && r.Status == "pre_merge" {
+		return json.Marshal(PreMergeBlockReward{Slot: &r.Slot, Status: r.Status, Code: r.Code})
+	}
+	type result SlotRewardResult // The conversion drops this method, so that encoding does not recurse.
+	return json.Marshal(result(r))
+}
+
+// PreMergeBlockReward represents the block reward of a slot before the merge. Its block carries no execution payload
+// and earns no execution-layer reward, so the reward is null, like the el_reward of a ProposerReward before the merge.
+type PreMergeBlockReward struct {
+	Slot   *uint64 `json:"slot,omitempty"` // The slot; absent for execution blocks from before the beacon chain genesis.
+	Status string  `json:"status"`         // Always "pre_merge".
+	Code   string  `json:"code"`           // Always "PRE_MERGE_SLOT".
+	Reward *string `json:"reward"`         // Always null.
+}
+
+// BatchRewardRequest represents the JSON body accepted by the batch block reward endpoint.
+type BatchRewardRequest struct {
+	Slots []uint64 `json:"slots"` // The slots to compute rewards for.
+}
+
+// RangeRewardResponse represents one page of block rewards for a contiguous slot range.
+type RangeRewardResponse struct {
+	Results    []SlotRewardResult `json:"results"`               // The results for the slots in this page, in slot order.
+	NextCursor string             `json:"next_cursor,omitempty"` // The cursor for the next page, omitted on the last page.
+}
+
+// RecipientRewards represents the blocks of a slot range that paid a given fee recipient, and their summed rewards.
+type RecipientRewards struct {
+	FeeRecipient  string             `json:"fee_recipient"`  // The fee recipient address, in lowercase.
+	FromSlot      uint64             `json:"from_slot"`      // The first slot of the range.
+	ToSlot        uint64             `json:"to_slot"`        // The last slot of the range, inclusive.
+	MatchedBlocks int                `json:"matched_blocks"` // The number of blocks whose fee recipient is the address.
+	FailedSlots   int                `json:"failed_slots"`   // The number of slots whose fee recipient or reward could not be determined.
+	Reward        string             `json:"reward"`         // The total priority-fee reward of the matching blocks, in gwei.
+	RewardWei     string             `json:"reward_wei"`     // The exact total priority-fee reward, in wei.
+	Blocks        []SlotRewardResult `json:"blocks"`         // The result for every matching slot, in slot order.
+}
+
+// EpochReward represents the block rewards of all slots in an epoch and their aggregate.
+// The totals only cover the proposed blocks; missed, pre-merge and failed slots contribute nothing.
+type EpochReward struct {
+	Epoch          uint64             `json:"epoch"`           // The epoch the rewards belong to.
+	StartSlot      uint64             `json:"start_slot"`      // The first slot of the epoch.
+	EndSlot        uint64             `json:"end_slot"`        // The last slot of the epoch.
+	ProposedBlocks int                `json:"proposed_blocks"` // The number of slots with a proposed block.
+	MissedSlots    int                `json:"missed_slots"`    // The number of slots without a block.
+	OrphanedSlots  int                `json:"orphaned_slots"`  // The number of slots whose block was reorged out.
+	PreMergeSlots  int                `json:"pre_merge_slots"` // The number of slots before the merge, which earn no execution-layer reward.
+	FailedSlots    int                `json:"failed_slots"`    // The number of slots whose reward could not be determined, including slots beyond the head.
+	Reward         string             `json:"reward"`          // The total priority-fee reward of the proposed blocks, in gwei.
+	RewardWei      string             `json:"reward_wei"`      // The exact total priority-fee reward, in wei.
+	BurntFees      string             `json:"burnt_fees"`      // The total base fees burnt by the proposed blocks, in gwei.
+	BurntFeesWei   string             `json:"burnt_fees_wei"`  // The exact total base fees burnt, in wei.
+	Slots          []SlotRewardResult `json:"slots"`           // The result for every slot of the epoch, in slot order.
+}
+
+// EpochRewardStats represents summary statistics of the block rewards of an epoch.
+// The reward statistics cover the proposed blocks only and are omitted when the epoch has none. Means and medians
+// that fall between two wei amounts are rounded down to the wei.
+type EpochRewardStats struct {
+	Epoch           uint64 `json:"epoch"`                       // The epoch the statistics belong to.
+	StartSlot       uint64 `json:"start_slot"`                  // The first slot of the epoch.
+	EndSlot         uint64 `json:"end_slot"`                    // The last slot of the epoch.
+	ProposedBlocks  int    `json:"proposed_blocks"`             // The number of slots with a proposed block.
+	RelayBlocks     int    `json:"relay_blocks"`                // The number of proposed blocks built through MEV-Boost relays.
+	VanillaBlocks   int    `json:"vanilla_blocks"`              // The number of proposed blocks built locally.
+	MissedSlots     int    `json:"missed_slots"`                // The number of slots without a block.
+	OrphanedSlots   int    `json:"orphaned_slots"`              // The number of slots whose block was reorged out.
+	PreMergeSlots   int    `json:"pre_merge_slots"`             // The number of slots before the merge, which earn no execution-layer reward.
+	FailedSlots     int    `json:"failed_slots"`                // The number of slots whose reward could not be determined, including slots beyond the head.
+	MinReward       string `json:"min_reward,omitempty"`        // The smallest priority-fee reward of a proposed block, in gwei.
+	MinRewardWei    string `json:"min_reward_wei,omitempty"`    // The exact smallest reward, in wei.
+	MaxReward       string `json:"max_reward,omitempty"`        // The largest priority-fee reward of a proposed block, in gwei.
+	MaxRewardWei    string `json:"max_reward_wei,omitempty"`    // The exact largest reward, in wei.
+	MeanReward      string `json:"mean_reward,omitempty"`       // The mean priority-fee reward of the proposed blocks, in gwei.
+	MeanRewardWei   string `json:"mean_reward_wei,omitempty"`   // The mean reward, in wei.
+	MedianReward    string `json:"median_reward,omitempty"`     // The median priority-fee reward of the proposed blocks, in gwei.
+	MedianRewardWei string `json:"median_reward_wei,omitempty"` // The median reward, in wei.
+}
+
+// ProposerReward represents the total reward earned by the proposer of a block, split by layer.
+// All amounts are denominated in gwei.
+type ProposerReward struct {
+	Code     string                 `json:"code,omitempty"` // "PRE_MERGE_SLOT" for blocks from before the merge, which earn consensus-layer rewards only.
+	ELReward *string                `json:"el_reward"`      // The execution-layer priority-fee reward; null before the merge.
+	CLReward string                 `json:"cl_reward"`      // The consensus-layer reward.
+	Total    string                 `json:"total"`          // The sum of the execution and consensus-layer rewards.
+	CLDetail ConsensusRewardDetails `json:"cl_breakdown"`   // The components of the consensus-layer reward.
+}
+
+// ConsensusRewardDetails represents the components of a proposer's consensus-layer reward, in gwei.
+type ConsensusRewardDetails struct {
+	Attestations      string `json:"attestations"`       // The reward for including attestations.
+	SyncAggregate     string `json:"sync_aggregate"`     // The reward for including the sync aggregate.
+	ProposerSlashings string `json:"proposer_slashings"` // The reward for including proposer slashings.
+	AttesterSlashings string `json:"attester_slashings"` // The reward for including attester slashings.
+}
+
+// SyncReward represents the sync committee reward earned by one committee member for a block, in gwei.
+type SyncReward struct {
+	ValidatorIndex string `json:"validator_index"` // The index of the validator.
+	Reward         string `json:"reward"`          // The reward earned, negative when the validator missed its duty.
+	Participated   bool   `json:"participated"`    // Indicates if the validator's signature was included in the sync aggregate.
+}
+
+// ValidatorKey pairs a validator index with its BLS public key.
+type ValidatorKey struct {
+	Index  string `json:"index"`  // The index of the validator.
+	Pubkey string `json:"pubkey"` // The BLS public key of the validator.
+}
+
+// ValidatorBlockRewards represents the aggregated block rewards of the slots a validator was scheduled to propose in an epoch range.
+type ValidatorBlockRewards struct {
+	ValidatorIndex uint64             `json:"validator_index"` // The index of the validator.
+	FromEpoch      uint64             `json:"from_epoch"`      // The first epoch of the range.
+	ToEpoch        uint64             `json:"to_epoch"`        // The last epoch of the range, inclusive.
+	ProposedBlocks int                `json:"proposed_blocks"` // The number of duties with a proposed block.
+	MissedSlots    int                `json:"missed_slots"`    // The number of duties without a block.
+	OrphanedSlots  int                `json:"orphaned_slots"`  // The number of duties whose block was reorged out.
+	PreMergeSlots  int                `json:"pre_merge_slots"` // The number of duties before the merge, which earn no execution-layer reward.
+	FailedSlots    int                `json:"failed_slots"`    // The number of duties whose reward could not be determined.
+	Reward         string             `json:"reward"`          // The total priority-fee reward of the proposed blocks, in gwei.
+	RewardWei      string             `json:"reward_wei"`      // The exact total priority-fee reward, in wei.
+	Slots          []SlotRewardResult `json:"slots"`           // The result for every slot the validator was scheduled to propose in, in slot order.
+}
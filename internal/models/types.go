@@ -3,30 +3,77 @@
 
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // BeaconBlockResponse represents the response structure for a beacon block request.
 // It contains nested structs to capture the version and execution payload details of the block.
 type BeaconBlockResponse struct {
 	Version string `json:"version"` // The version of the beacon block.
 	Data    struct {
 		Message struct {
-			Body struct {
+			Slot          string `json:"slot"`           // The slot the block was proposed in.
+			ProposerIndex string `json:"proposer_index"` // The index of the validator that proposed the block.
+			ParentRoot    string `json:"parent_root"`    // The root of the parent beacon block.
+			StateRoot     string `json:"state_root"`     // The root of the beacon state after the block, which identifies the block.
+			Body          struct {
 				ExecutionPayload struct {
+					BlockNumber   string       `json:"block_number"`     // The block number in the execution payload.
+					BlockHash     string       `json:"block_hash"`       // The hash of the execution block.
+					FeeRecipient  string       `json:"fee_recipient"`    // The address that receives the transaction fees.
+					ExtraData     string       `json:"extra_data"`       // Additional data included in the block.
+					BaseFeePerGas string       `json:"base_fee_per_gas"` // The base fee per gas unit for the block.
+					GasUsed       string       `json:"gas_used"`         // The total gas used by transactions in the block.
+					Withdrawals   []Withdrawal `json:"withdrawals"`      // The validator withdrawals processed by the block; absent before Shanghai.
+				} `json:"execution_payload"`
+				ExecutionPayloadHeader struct {
 					BlockNumber   string `json:"block_number"`     // The block number in the execution payload.
+					BlockHash     string `json:"block_hash"`       // The hash of the execution block.
 					FeeRecipient  string `json:"fee_recipient"`    // The address that receives the transaction fees.
 					ExtraData     string `json:"extra_data"`       // Additional data included in the block.
 					BaseFeePerGas string `json:"base_fee_per_gas"` // The base fee per gas unit for the block.
 					GasUsed       string `json:"gas_used"`         // The total gas used by transactions in the block.
-				} `json:"execution_payload"`
+				} `json:"execution_payload_header"` // The header of the execution payload, served instead of the payload for blinded blocks.
 			} `json:"body"`
 		} `json:"message"`
 	} `json:"data"`
 }
 
+// UnblindPayload fills the execution payload of a blinded block, which carries only the payload's header, from that
+// header, so that blinded and full blocks can be processed alike. Headers do not list the withdrawals, so they stay absent.
+// Blocks with a full execution payload are left untouched.
+func (b *BeaconBlockResponse) UnblindPayload() {
+	body := &b.Data.Message.Body
+	if body.ExecutionPayload.BlockNumber != "" || body.ExecutionPayloadHeader.BlockNumber == "" {
+		return
+	}
+	header := body.ExecutionPayloadHeader
+	body.ExecutionPayload.BlockNumber = header.BlockNumber
+	body.ExecutionPayload.BlockHash = header.BlockHash
+	body.ExecutionPayload.FeeRecipient = header.FeeRecipient
+	body.ExecutionPayload.ExtraData = header.ExtraData
+	body.ExecutionPayload.BaseFeePerGas = header.BaseFeePerGas
+	body.ExecutionPayload.GasUsed = header.GasUsed
+}
+
+// Withdrawal represents a validator withdrawal processed by an execution payload.
+type Withdrawal struct {
+	Index          string `json:"index"`           // The global index of the withdrawal.
+	ValidatorIndex string `json:"validator_index"` // The index of the validator whose balance was withdrawn.
+	Address        string `json:"address"`         // The execution-layer address the amount was credited to.
+	Amount         string `json:"amount"`          // The amount withdrawn, in gwei.
+}
+
 // BeaconHeadersResponse represents the response structure for beacon headers.
 // It includes a list of headers, each containing a message with a slot identifier.
+// Queried by slot, it lists every block the node knows at that slot, including blocks that were reorged out.
 type BeaconHeadersResponse struct {
 	Data []struct {
-		Header struct {
+		Root      string `json:"root"`      // The root of the beacon block.
+		Canonical bool   `json:"canonical"` // Indicates if the block is part of the canonical chain.
+		Header    struct {
 			Message struct {
 				Slot string `json:"slot"` // The slot number associated with the beacon header.
 			} `json:"message"`
@@ -37,18 +84,37 @@ type BeaconHeadersResponse struct {
 // ExecutionBlockTx represents a transaction within an execution block.
 // It includes various fields such as block hash, gas details, and transaction identifiers.
 type ExecutionBlockTx struct {
-	BlockHash        string `json:"blockHash"`        // The hash of the block containing the transaction.
-	BlockNumber      string `json:"blockNumber"`      // The block number containing the transaction.
-	From             string `json:"from"`             // The address that initiated the transaction.
-	Gas              string `json:"gas"`              // The gas limit provided by the sender.
-	GasPrice         string `json:"gasPrice"`         // The price per gas unit offered by the sender.
-	Hash             string `json:"hash"`             // The hash of the transaction.
-	Input            string `json:"input"`            // The input data for the transaction.
-	Nonce            string `json:"nonce"`            // The number of transactions sent from the sender's address.
-	To               string `json:"to"`               // The address of the recipient.
-	TransactionIndex string `json:"transactionIndex"` // The index of the transaction within the block.
-	Value            string `json:"value"`            // The amount of Ether transferred.
-	Type             string `json:"type"`             // The type of transaction.
+	BlockHash            string `json:"blockHash"`            // The hash of the block containing the transaction.
+	BlockNumber          string `json:"blockNumber"`          // The block number containing the transaction.
+	From                 string `json:"from"`                 // The address that initiated the transaction.
+	Gas                  string `json:"gas"`                  // The gas limit provided by the sender.
+	GasPrice             string `json:"gasPrice"`             // The price per gas unit offered by the sender.
+	MaxFeePerGas         string `json:"maxFeePerGas"`         // The maximum total fee per gas for dynamic fee transactions.
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"` // The maximum priority fee per gas for dynamic fee transactions.
+	Hash                 string `json:"hash"`                 // The hash of the transaction.
+	Input                string `json:"input"`                // The input data for the transaction.
+	Nonce                string `json:"nonce"`                // The number of transactions sent from the sender's address.
+	To                   string `json:"to"`                   // The address of the recipient.
+	TransactionIndex     string `json:"transactionIndex"`     // The index of the transaction within the block.
+	Value                string `json:"value"`                // The amount of Ether transferred.
+	Type                 string `json:"type"`                 // The type of transaction.
+
+	BlobVersionedHashes []string `json:"blobVersionedHashes"` // The versioned hashes of the blobs carried by a blob (type 3) transaction.
+	MaxFeePerBlobGas    string   `json:"maxFeePerBlobGas"`    // The maximum fee per blob gas a blob transaction is willing to pay.
+
+	HashOnly bool `json:"-"` // Whether the block was fetched with transaction hashes only, so that only Hash was set by the node.
+}
+
+// UnmarshalJSON decodes a transaction either as a full transaction object or, for blocks fetched without full
+// transactions, as its bare hash, in which case only Hash is set and HashOnly is true.
+func (tx *ExecutionBlockTx) UnmarshalJSON(data []byte) error {
+	var hash string
+	if err := json.Unmarshal(data, &hash); err == nil {
+		*tx = ExecutionBlockTx{Hash: hash, HashOnly: true}
+		return nil
+	}
+	type plain ExecutionBlockTx // Drops the methods of ExecutionBlockTx so that decoding does not recurse.
+	return json.Unmarshal(data, (*plain)(tx))
 }
 
 // ExecutionBlockFullResponse represents the full response for an execution block request.
@@ -56,10 +122,19 @@ type ExecutionBlockTx struct {
 type ExecutionBlockFullResponse struct {
 	Result struct {
 		Number        string             `json:"number"`        // The block number.
+		Hash          string             `json:"hash"`          // The hash of the block.
+		ParentHash    string             `json:"parentHash"`    // The hash of the parent block.
+		Timestamp     string             `json:"timestamp"`     // The Unix time of the block, in hexadecimal.
+		GasUsed       string             `json:"gasUsed"`       // The total gas used by the block's transactions.
+		GasLimit      string             `json:"gasLimit"`      // The maximum gas the block may use.
 		BaseFeePerGas string             `json:"baseFeePerGas"` // The base fee per gas unit for the block.
 		ExtraData     string             `json:"extraData"`     // Additional data included in the block.
 		Transactions  []ExecutionBlockTx `json:"transactions"`  // A list of transactions in the block.
+		BlobGasUsed   string             `json:"blobGasUsed"`   // The total blob gas used by the block's blob transactions; absent before Dencun.
+		ExcessBlobGas string             `json:"excessBlobGas"` // The excess blob gas the blob base fee is derived from; absent before Dencun.
 	} `json:"result"`
+	Error *JSONRPCError `json:"error"` // The error reported by the node in place of a result, if any.
+	Id    int           `json:"id"`    // The id of the request this response answers.
 }
 
 // SyncCommitteeResponse represents the response from the sync_committees endpoint.
@@ -71,3 +146,188 @@ type SyncCommitteeResponse struct {
 		Validators []string `json:"validators"` // A list of validator addresses in the sync committee.
 	} `json:"data"`
 }
+
+// TransactionReceipt represents a single transaction receipt within an execution block.
+// It carries the gas actually consumed by the transaction, which can be far lower than the gas limit.
+type TransactionReceipt struct {
+	TransactionHash   string `json:"transactionHash"`   // The hash of the transaction.
+	TransactionIndex  string `json:"transactionIndex"`  // The index of the transaction within the block.
+	From              string `json:"from"`              // The address that sent the transaction.
+	To                string `json:"to"`                // The recipient of the transaction; empty for contract creations.
+	Type              string `json:"type"`              // The type of the transaction.
+	GasUsed           string `json:"gasUsed"`           // The amount of gas consumed by the transaction.
+	EffectiveGasPrice string `json:"effectiveGasPrice"` // The price per gas unit actually paid by the sender.
+	Status            string `json:"status"`            // 0x1 on success, 0x0 on failure.
+	BlobGasUsed       string `json:"blobGasUsed"`       // The blob gas consumed by a blob transaction.
+	BlobGasPrice      string `json:"blobGasPrice"`      // The blob base fee paid per unit of blob gas, burnt in full.
+}
+
+// BlockReceiptsResponse represents the response for an eth_getBlockReceipts request.
+// It includes the receipts of every transaction in the block.
+type BlockReceiptsResponse struct {
+	Result []TransactionReceipt `json:"result"` // A list of receipts for the transactions in the block.
+	Error  *JSONRPCError        `json:"error"`  // The error reported by the node in place of a result, if any.
+	Id     int                  `json:"id"`     // The id of the request this response answers.
+}
+
+// BalanceResponse represents the response for an eth_getBalance request.
+type BalanceResponse struct {
+	Result string        `json:"result"` // The balance in wei, in hexadecimal.
+	Error  *JSONRPCError `json:"error"`  // The error reported by the node in place of a result, if any.
+	Id     int           `json:"id"`     // The id of the request this response answers.
+}
+
+// SpecResponse represents the response from the /eth/v1/config/spec endpoint.
+// Most constants are decimal strings, but some, such as fork schedules, are lists or objects.
+type SpecResponse struct {
+	Data map[string]json.RawMessage `json:"data"` // The configuration constants of the network, keyed by name.
+}
+
+// GenesisResponse represents the response from the /eth/v1/beacon/genesis endpoint.
+type GenesisResponse struct {
+	Data struct {
+		GenesisTime           string `json:"genesis_time"`            // The Unix time of the beacon chain genesis.
+		GenesisValidatorsRoot string `json:"genesis_validators_root"` // The root of the genesis validator registry, which identifies the chain.
+		GenesisForkVersion    string `json:"genesis_fork_version"`    // The fork version the chain started with.
+	} `json:"data"`
+}
+
+// QuantityResponse represents the response for a JSON-RPC request whose result is a single quantity,
+// such as eth_blockNumber or eth_chainId.
+type QuantityResponse struct {
+	Result string        `json:"result"` // The quantity, in hexadecimal.
+	Error  *JSONRPCError `json:"error"`  // The error reported by the node in place of a result, if any.
+	Id     int           `json:"id"`     // The id of the request this response answers.
+}
+
+// JSONRPCError represents the error object a JSON-RPC node returns instead of a result when a call fails.
+// Nodes answer such calls with HTTP 200, so the error object is the only sign of the failure.
+type JSONRPCError struct {
+	Code    int    `json:"code"`    // The error code, e.g. -32000 for server errors.
+	Message string `json:"message"` // The description of the error.
+}
+
+// Error returns the code and message of the JSON-RPC error.
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// BidTrace represents a payload delivery reported by the data API of an MEV-Boost relay.
+// All amounts are decimal strings; the value is in wei.
+type BidTrace struct {
+	Slot                 string `json:"slot"`                   // The slot the payload was delivered for.
+	BlockHash            string `json:"block_hash"`             // The hash of the delivered execution block.
+	BlockNumber          string `json:"block_number"`           // The number of the delivered execution block.
+	BuilderPubkey        string `json:"builder_pubkey"`         // The BLS public key of the builder that built the block.
+	ProposerPubkey       string `json:"proposer_pubkey"`        // The BLS public key of the proposer the payload was delivered to.
+	ProposerFeeRecipient string `json:"proposer_fee_recipient"` // The address the builder paid the bid value to.
+	Value                string `json:"value"`                  // The bid value paid to the proposer, in wei.
+}
+
+// BeaconHeaderResponse represents the response structure for a single beacon header request.
+// It includes the block root and the header message identifying the slot and its parent.
+type BeaconHeaderResponse struct {
+	Data struct {
+		Root      string `json:"root"`      // The root of the beacon block.
+		Canonical bool   `json:"canonical"` // Indicates if the block is part of the canonical chain.
+		Header    struct {
+			Message struct {
+				Slot          string `json:"slot"`           // The slot number of the beacon block.
+				ProposerIndex string `json:"proposer_index"` // The index of the validator that proposed the block.
+				ParentRoot    string `json:"parent_root"`    // The root of the parent beacon block.
+				StateRoot     string `json:"state_root"`     // The root of the beacon state after the block.
+			} `json:"message"`
+		} `json:"header"`
+	} `json:"data"`
+}
+
+// Checkpoint represents a beacon chain checkpoint identified by an epoch and a block root.
+type Checkpoint struct {
+	Epoch string `json:"epoch"` // The epoch of the checkpoint.
+	Root  string `json:"root"`  // The block root of the checkpoint.
+}
+
+// FinalityCheckpointsResponse represents the response from the finality_checkpoints endpoint.
+// It includes the previous justified, current justified, and finalized checkpoints of a state.
+type FinalityCheckpointsResponse struct {
+	Data struct {
+		PreviousJustified Checkpoint `json:"previous_justified"` // The previously justified checkpoint.
+		CurrentJustified  Checkpoint `json:"current_justified"`  // The currently justified checkpoint.
+		Finalized         Checkpoint `json:"finalized"`          // The latest finalized checkpoint.
+	} `json:"data"`
+}
+
+// ConsensusBlockRewardsResponse represents the response from the beacon block rewards endpoint.
+// It breaks down the consensus-layer reward earned by the proposer of a block; all amounts are in gwei.
+type ConsensusBlockRewardsResponse struct {
+	ExecutionOptimistic bool `json:"execution_optimistic"` // Indicates if the execution is optimistic.
+	Finalized           bool `json:"finalized"`            // Indicates if the data is finalized.
+	Data                struct {
+		ProposerIndex     string `json:"proposer_index"`     // The index of the validator that proposed the block.
+		Total             string `json:"total"`              // The total consensus-layer reward for the block.
+		Attestations      string `json:"attestations"`       // The reward for including attestations.
+		SyncAggregate     string `json:"sync_aggregate"`     // The reward for including the sync aggregate.
+		ProposerSlashings string `json:"proposer_slashings"` // The reward for including proposer slashings.
+		AttesterSlashings string `json:"attester_slashings"` // The reward for including attester slashings.
+	} `json:"data"`
+}
+
+// AttestationReward represents the attestation reward components earned by a validator during an epoch, in gwei.
+type AttestationReward struct {
+	ValidatorIndex string `json:"validator_index"`           // The index of the validator.
+	Head           string `json:"head"`                      // The reward for a correct head vote.
+	Target         string `json:"target"`                    // The reward (or penalty) for the target vote.
+	Source         string `json:"source"`                    // The reward (or penalty) for the source vote.
+	InclusionDelay string `json:"inclusion_delay,omitempty"` // The inclusion delay reward, reported for phase0 only.
+	Inactivity     string `json:"inactivity"`                // The inactivity penalty.
+}
+
+// AttestationRewardsResponse represents the response from the beacon attestation rewards endpoint.
+// It includes the rewards each requested validator earned for its attestations in an epoch.
+type AttestationRewardsResponse struct {
+	ExecutionOptimistic bool `json:"execution_optimistic"` // Indicates if the execution is optimistic.
+	Finalized           bool `json:"finalized"`            // Indicates if the data is finalized.
+	Data                struct {
+		TotalRewards []AttestationReward `json:"total_rewards"` // The rewards earned by each validator.
+	} `json:"data"`
+}
+
+// SyncCommitteeReward represents the sync committee reward (or penalty) earned by a validator in a block, in gwei.
+type SyncCommitteeReward struct {
+	ValidatorIndex string `json:"validator_index"` // The index of the validator.
+	Reward         string `json:"reward"`          // The reward for participating, negative for a missed duty.
+}
+
+// SyncCommitteeRewardsResponse represents the response from the beacon sync committee rewards endpoint.
+type SyncCommitteeRewardsResponse struct {
+	ExecutionOptimistic bool                  `json:"execution_optimistic"` // Indicates if the execution is optimistic.
+	Finalized           bool                  `json:"finalized"`            // Indicates if the data is finalized.
+	Data                []SyncCommitteeReward `json:"data"`                 // The reward earned by each sync committee member.
+}
+
+// ValidatorsResponse represents the response from the beacon state validators endpoint.
+// It includes the index and public key of each requested validator.
+type ValidatorsResponse struct {
+	Data []struct {
+		Index     string `json:"index"` // The index of the validator.
+		Validator struct {
+			Pubkey string `json:"pubkey"` // The BLS public key of the validator.
+		} `json:"validator"`
+	} `json:"data"`
+}
+
+// ProposerDuty represents the validator scheduled to propose the block of a slot.
+// The beacon API encodes the index and slot as decimal strings.
+type ProposerDuty struct {
+	Pubkey         string `json:"pubkey"`                 // The BLS public key of the proposer.
+	ValidatorIndex uint64 `json:"validator_index,string"` // The index of the proposer.
+	Slot           uint64 `json:"slot,string"`            // The slot the validator is scheduled to propose in.
+}
+
+// ProposerDutiesResponse represents the response from the beacon proposer duties endpoint.
+// It lists the validator scheduled to propose in each slot of an epoch.
+type ProposerDutiesResponse struct {
+	DependentRoot       string         `json:"dependent_root"`       // The block root the duties were computed from; duties change if it is reorged out.
+	ExecutionOptimistic bool           `json:"execution_optimistic"` // Indicates if the execution is optimistic.
+	Data                []ProposerDuty `json:"data"`                 // The proposer of each slot of the epoch.
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"eth-rewards-api/internal/models"
+)
+
+// maxBatchCallSize caps the number of calls sent in one JSON-RPC batch, since providers limit the size of batches they accept.
+const maxBatchCallSize = 100
+
+// jsonRPCResponse represents a single response of a JSON-RPC batch.
+type jsonRPCResponse struct {
+	Id     int                  `json:"id"`
+	Result json.RawMessage      `json:"result"`
+	Error  *models.JSONRPCError `json:"error"`
+}
+
+// BatchError reports the calls of a JSON-RPC batch that failed while the others succeeded.
+// Errors is aligned with the requests passed to BatchCall; it holds nil for every call that succeeded.
+type BatchError struct {
+	Errors []error
+}
+
+// Error summarizes the failed calls of the batch.
+func (e *BatchError) Error() string {
+	failed := 0
+	var first error
+	for _, err := range e.Errors {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	return fmt.Sprintf("%d of %d batched calls failed, first error: %v", failed, len(e.Errors), first)
+}
+
+// BatchCall sends the requests as JSON-RPC batches, so that many calls share a round trip.
+// The requests are given fresh, consecutive ids to match the responses, which may arrive in any order.
+// The results are returned in request order. If only some calls fail, the results of the others are still returned
+// together with a *BatchError describing the failures; the results of failed calls are nil.
+func (e *ExecutionService) BatchCall(ctx context.Context, requests []JSONRPCRequest) ([]json.RawMessage, error) {
+	results := make([]json.RawMessage, len(requests))
+	errs := make([]error, len(requests))
+	failed := false
+
+	for start := 0; start < len(requests); start += maxBatchCallSize {
+		end := min(start+maxBatchCallSize, len(requests))
+		if err := e.batchCall(ctx, requests[start:end], results[start:end], errs[start:end]); err != nil {
+			return nil, err // The whole batch failed, so no call has a result.
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			failed = true
+		}
+	}
+	if failed {
+		return results, &BatchError{Errors: errs}
+	}
+	return results, nil
+}
+
+// batchCall sends a single JSON-RPC batch and stores the result or error of every call at its position.
+// It returns an error only if the batch as a whole failed.
+func (e *ExecutionService) batchCall(ctx context.Context, requests []JSONRPCRequest, results []json.RawMessage, errs []error) error {
+	// Reserve a block of consecutive ids, so that the position of a call is its id minus the first id.
+	firstID := int(e.lastID.Add(int64(len(requests)))) - len(requests) + 1
+	batch := make([]JSONRPCRequest, len(requests))
+	for i, req := range requests {
+		req.Id = firstID + i
+		batch[i] = req
+	}
+	// Marshal the batch into a JSON array.
+	b, _ := json.Marshal(batch)
+	// Send a POST request to the execution endpoint with the JSON-RPC batch.
+	resp, err := e.post(ctx, b)
+	if err != nil {
+		return err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	// Check if the response status code is not 200 OK.
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError(resp, "") // Handle non-200 HTTP responses.
+	}
+
+	// Decode the JSON response body into one response per call.
+	var responses []jsonRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
+		return err // Return an error if JSON decoding fails, e.g. when the endpoint rejects batches with a single error object.
+	}
+
+	// Match every response to its call by id.
+	answered := make([]bool, len(requests))
+	for _, response := range responses {
+		i := response.Id - firstID
+		if i < 0 || i >= len(requests) || answered[i] {
+			continue // Ignore responses that do not belong to a call of this batch.
+		}
+		answered[i] = true
+		if response.Error != nil {
+			errs[i] = response.Error
+			continue
+		}
+		results[i] = response.Result
+	}
+	for i := range requests {
+		if !answered[i] {
+			errs[i] = fmt.Errorf("no response to %s call in batch", requests[i].Method)
+		}
+	}
+	return nil
+}
+
+// BlockWithReceipts pairs an execution block with the receipts of its transactions, or the error that prevented fetching them.
+type BlockWithReceipts struct {
+	Block    *models.ExecutionBlockFullResponse
+	Receipts []models.TransactionReceipt // The receipts keyed by transaction index, like those returned by GetBlockReceipts.
+	Err      error
+}
+
+// GetBlocksWithReceipts retrieves several execution blocks, and their receipts, in batched JSON-RPC calls.
+// Like GetExecutionBlockByNumber, the blocks carry transaction hashes only if the service was created WithTransactionHashesOnly.
+// The results are returned in the order of the block numbers; a block that could not be fetched carries its error.
+func (e *ExecutionService) GetBlocksWithReceipts(ctx context.Context, blockNumberHexes []string) ([]BlockWithReceipts, error) {
+	// Request every block and its receipts side by side: calls 2i and 2i+1 belong to block i.
+	requests := make([]JSONRPCRequest, 0, 2*len(blockNumberHexes))
+	for _, blockNumberHex := range blockNumberHexes {
+		requests = append(requests,
+			JSONRPCRequest{Jsonrpc: "2.0", Method: "eth_getBlockByNumber", Params: []interface{}{blockNumberHex, e.fullTransactions}},
+			JSONRPCRequest{Jsonrpc: "2.0", Method: "eth_getBlockReceipts", Params: []interface{}{blockNumberHex}},
+		)
+	}
+
+	results, err := e.BatchCall(ctx, requests)
+	var batchErr *BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		return nil, err
+	}
+	callErr := func(i int) error {
+		if batchErr == nil {
+			return nil
+		}
+		return batchErr.Errors[i]
+	}
+
+	blocks := make([]BlockWithReceipts, len(blockNumberHexes))
+	for i := range blockNumberHexes {
+		if err := errors.Join(callErr(2*i), callErr(2*i+1)); err != nil {
+			blocks[i].Err = err
+			continue
+		}
+
+		var block models.ExecutionBlockFullResponse
+		if err := json.Unmarshal(results[2*i], &block.Result); err != nil {
+			blocks[i].Err = err
+			continue
+		}
+		if block.Result.Number == "" {
+			blocks[i].Err = ErrExecutionBlockNotFound
+			continue
+		}
+		var receipts []models.TransactionReceipt
+		if err := json.Unmarshal(results[2*i+1], &receipts); err != nil {
+			blocks[i].Err = err
+			continue
+		}
+		if receipts == nil {
+			// As for GetBlockReceipts, null receipts mean the node does not know the block yet.
+			blocks[i].Err = ErrExecutionBlockNotFound
+			continue
+		}
+		ordered, err := orderReceipts(receipts)
+		if err != nil {
+			blocks[i].Err = err
+			continue
+		}
+		blocks[i].Block = &block
+		blocks[i].Receipts = ordered
+	}
+	return blocks, nil
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// coalesce calls fetch for key unless a call for the same key is already in flight, in which case it waits for that call's result.
+// The shared call runs with a context that is not canceled with ctx, since other callers may still be waiting for it;
+// a caller whose ctx is done stops waiting and returns the context's error.
+func coalesce[T any](ctx context.Context, group *singleflight.Group, key string, fetch func(context.Context) (T, error)) (T, error) {
+	shared := context.WithoutCancel(ctx)
+	ch := group.DoChan(key, func() (interface{}, error) {
+		return fetch(shared)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			var zero T
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
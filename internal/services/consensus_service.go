@@ -4,47 +4,140 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"eth-rewards-api/internal/cache"
 	"eth-rewards-api/internal/models"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // SLOTS_PER_EPOCH is a constant that defines the number of slots in a single epoch on the Ethereum mainnet.
+// The service uses the value the beacon node reports when it can; see NetworkConfig.WithSpec.
 const SLOTS_PER_EPOCH = 32
 
-// ConsensusService is a struct that holds the endpoint URL and an HTTP client for making requests.
+// SECONDS_PER_SLOT is a constant that defines the duration of a slot in seconds on the Ethereum mainnet.
+const SECONDS_PER_SLOT = 12
+
+// MAINNET_GENESIS_TIME is a constant that defines the Unix time of the Ethereum mainnet beacon chain genesis.
+const MAINNET_GENESIS_TIME = 1606824023
+
+// EPOCHS_PER_SYNC_COMMITTEE_PERIOD is a constant that defines how many epochs a sync committee serves before it rotates.
+const EPOCHS_PER_SYNC_COMMITTEE_PERIOD = 256
+
+// syncCommitteeCacheSize is the number of sync committee periods whose committees are kept in memory.
+const syncCommitteeCacheSize = 16
+
+// ConsensusService is a struct that holds the endpoint URL, an HTTP client for making requests, and the retry policy applied to them.
 type ConsensusService struct {
 	endpoint string
 	client   *http.Client
+	retry    RetryPolicy
+
+	network NetworkConfig // The chain parameters of the network the endpoint serves.
+
+	headSlot       *headSlotCache               // The recently fetched head slot.
+	blocks         singleflight.Group           // Coalesces concurrent requests for the same beacon block.
+	syncCommittees *cache.LRU[uint64, []string] // The validators of recently requested sync committees, keyed by period.
+
+	spec    staticValue[Spec]                    // The configuration constants of the network, once fetched.
+	genesis staticValue[*models.GenesisResponse] // The genesis of the beacon chain, once fetched.
 }
 
 // NewConsensusService initializes a new instance of ConsensusService with a specified endpoint and a default HTTP client.
-func NewConsensusService(endpoint string) *ConsensusService {
+// Optional settings such as the retry policy can be overridden with opts.
+func NewConsensusService(endpoint string, opts ...Option) *ConsensusService {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
 	return &ConsensusService{
 		endpoint: endpoint,
 		client: &http.Client{
-			Timeout: 10 * time.Second, // Sets a timeout for HTTP requests.
+			Timeout:   o.timeout,                    // Sets a timeout for HTTP requests.
+			Transport: o.httpTransport("consensus"), // A nil transport falls back to http.DefaultTransport.
 		},
+		retry:          o.retry,
+		network:        o.network,
+		headSlot:       &headSlotCache{ttl: o.headSlotTTL},
+		syncCommittees: cache.NewLRU[uint64, []string](syncCommitteeCacheSize),
 	}
 }
 
+// Network returns the chain parameters of the network the consensus service is configured for.
+func (c *ConsensusService) Network() NetworkConfig {
+	return c.network
+}
+
+// SlotsPerEpoch returns the number of slots in an epoch on the configured network.
+func (c *ConsensusService) SlotsPerEpoch() uint64 {
+	return c.network.SlotsPerEpoch
+}
+
+// SlotToTime returns the start time of a slot, computed as genesis time + slot * seconds per slot.
+func (c *ConsensusService) SlotToTime(slot uint64) time.Time {
+	return c.network.Genesis().Add(time.Duration(slot*c.network.SecondsPerSlot) * time.Second)
+}
+
+// TimeToSlot returns the slot that is active at the given time.
+// It returns an error if the time lies before genesis.
+func (c *ConsensusService) TimeToSlot(t time.Time) (uint64, error) {
+	genesis := c.network.Genesis()
+	if t.Before(genesis) {
+		return 0, errors.New("time is before genesis")
+	}
+	return uint64(t.Sub(genesis)/time.Second) / c.network.SecondsPerSlot, nil
+}
+
+// get sends a GET request to the given URL, retrying transient failures according to the retry policy.
+// The request is canceled when ctx is done.
+func (c *ConsensusService) get(ctx context.Context, url string) (*http.Response, error) {
+	return doWithRetry(ctx, "consensus", c.client, c.retry, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	})
+}
+
+// post sends a POST request with a JSON body to the given URL, retrying transient failures according to the retry policy.
+// It is used by the beacon reward endpoints, which are read-only despite taking a request body.
+func (c *ConsensusService) post(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	return doWithRetry(ctx, "consensus", c.client, c.retry, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+}
+
 // GetHeadSlot retrieves the current head slot number from the beacon chain headers endpoint.
 // It returns the slot number as a uint64 and an error if any issues occur during the request or data parsing.
-func (c *ConsensusService) GetHeadSlot() (uint64, error) {
+// The head slot is served from memory for the configured TTL, and concurrent callers share a single upstream request.
+func (c *ConsensusService) GetHeadSlot(ctx context.Context) (uint64, error) {
+	return c.headSlot.get(ctx, c.FetchHeadSlot)
+}
+
+// FetchHeadSlot requests the current head slot number from the beacon chain headers endpoint, bypassing the head slot
+// cache. Readiness checks use it, since a cached head slot, or one kept current by the head event stream, does not tell
+// whether the beacon node still answers.
+func (c *ConsensusService) FetchHeadSlot(ctx context.Context) (uint64, error) {
 	url := fmt.Sprintf("%s/eth/v1/beacon/headers", c.endpoint)
-	resp, err := c.client.Get(url)
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return 0, err // Return an error if the HTTP request fails.
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode) // Handle non-200 HTTP responses.
+		return 0, newStatusError(resp, "") // Handle non-200 HTTP responses.
 	}
 
 	var headersResp models.BeaconHeadersResponse
@@ -64,45 +157,93 @@ func (c *ConsensusService) GetHeadSlot() (uint64, error) {
 
 // GetBeaconBlockBySlot fetches the beacon block for a given slot number.
 // It returns a pointer to a BeaconBlockResponse and an error if any issues occur during the request or data parsing.
-func (c *ConsensusService) GetBeaconBlockBySlot(slot uint64) (*models.BeaconBlockResponse, error) {
-	url := fmt.Sprintf("%s/eth/v2/beacon/blocks/%d", c.endpoint, slot)
-	resp, err := c.client.Get(url)
+// Concurrent requests for the same slot share a single upstream call, so the returned block must not be modified.
+func (c *ConsensusService) GetBeaconBlockBySlot(ctx context.Context, slot uint64) (*models.BeaconBlockResponse, error) {
+	return c.GetBeaconBlock(ctx, strconv.FormatUint(slot, 10))
+}
+
+// GetBeaconBlock fetches the beacon block for a block identifier (head, finalized, a slot, or a 0x-prefixed block root).
+// Concurrent requests for the same block share a single upstream call, so the returned block must not be modified.
+func (c *ConsensusService) GetBeaconBlock(ctx context.Context, blockID string) (*models.BeaconBlockResponse, error) {
+	return coalesce(ctx, &c.blocks, blockID, func(ctx context.Context) (*models.BeaconBlockResponse, error) {
+		return c.fetchBeaconBlock(ctx, blockID)
+	})
+}
+
+// fetchBeaconBlock requests the beacon block for a block identifier from the beacon node.
+// Some nodes serve the blocks of MEV-Boost proposals blinded, as the /eth/v1/beacon/blinded_blocks endpoint does,
+// with only the header of the execution payload; its fields are copied into the payload so the block is usable.
+func (c *ConsensusService) fetchBeaconBlock(ctx context.Context, blockID string) (*models.BeaconBlockResponse, error) {
+	url := fmt.Sprintf("%s/eth/v2/beacon/blocks/%s", c.endpoint, blockID)
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err // Return an error if the HTTP request fails.
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, errors.New("block not found") // Handle 404 response.
+		return nil, ErrBlockNotFound // Handle 404 response.
 	} else if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode) // Handle non-200 HTTP responses.
+		return nil, newStatusError(resp, "") // Handle non-200 HTTP responses.
 	}
 
 	var blockResp models.BeaconBlockResponse
 	if err := json.NewDecoder(resp.Body).Decode(&blockResp); err != nil {
 		return nil, err // Return an error if JSON decoding fails.
 	}
+	blockResp.UnblindPayload()
 	return &blockResp, nil // Return the beacon block response.
 }
 
+// SyncCommitteePeriod returns the sync committee period containing the given slot.
+// Every slot within the same period is served by the same sync committee.
+func (c *ConsensusService) SyncCommitteePeriod(slot uint64) uint64 {
+	return slot / c.network.SlotsPerEpoch / c.network.SyncPeriodEpochs
+}
+
 // GetSyncCommitteeDuties retrieves the sync committee validators for a specified slot.
-// It calculates the epoch and constructs the state_id to fetch the relevant data.
-// Returns a slice of validator addresses and an error if any issues occur during the request or data parsing.
-func (c *ConsensusService) GetSyncCommitteeDuties(slot uint64) ([]string, error) {
-	epoch := slot / SLOTS_PER_EPOCH
-	state_id := epoch * SLOTS_PER_EPOCH // Calculate the first slot of the epoch.
-	url := fmt.Sprintf("%s/eth/v1/beacon/states/%d/sync_committees?epoch=%d", c.endpoint, state_id, epoch)
-
-	resp, err := c.client.Get(url)
+// It returns the committee of the slot's sync committee period, see GetSyncCommittee.
+func (c *ConsensusService) GetSyncCommitteeDuties(ctx context.Context, slot uint64) ([]string, error) {
+	return c.GetSyncCommittee(ctx, c.SyncCommitteePeriod(slot))
+}
+
+// GetSyncCommittee retrieves the validators of the sync committee serving the given period.
+// A period's committee never changes, so it is cached; the returned slice is shared and must not be modified.
+// Returns a slice of validator indices and an error if any issues occur during the request or data parsing.
+func (c *ConsensusService) GetSyncCommittee(ctx context.Context, period uint64) ([]string, error) {
+	if validators, ok := c.syncCommittees.Get(period); ok {
+		return validators, nil
+	}
+	validators, err := c.fetchSyncCommittee(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	c.syncCommittees.Add(period, validators)
+	return validators, nil
+}
+
+// fetchSyncCommittee requests the sync committee of a period from the state at the first slot of the period.
+// The committee of the period after the current one is already known to the head state, which is used when the period has not started yet.
+func (c *ConsensusService) fetchSyncCommittee(ctx context.Context, period uint64) ([]string, error) {
+	epoch := period * c.network.SyncPeriodEpochs                      // The first epoch of the sync committee period.
+	state_id := strconv.FormatUint(epoch*c.network.SlotsPerEpoch, 10) // Calculate the first slot of the sync committee period.
+	if headSlot, err := c.GetHeadSlot(ctx); err == nil && period > c.SyncCommitteePeriod(headSlot) {
+		state_id = "head"
+	}
+	url := fmt.Sprintf("%s/eth/v1/beacon/states/%s/sync_committees?epoch=%d", c.endpoint, state_id, epoch)
+
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err // Return an error if the HTTP request fails.
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, errors.New("sync committee duties not found for this slot") // Handle 404 response.
+	// Beacon nodes answer 404 for unknown states and 400 for states from before Altair, which have no sync committee.
+	// The state and epoch are always well-formed here, so either way the slot has no sync committee.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		return nil, ErrSyncCommitteeNotFound // Handle 404 and 400 responses.
 	} else if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d from sync duties endpoint", resp.StatusCode) // Handle non-200 HTTP responses.
+		return nil, newStatusError(resp, "sync duties endpoint") // Handle non-200 HTTP responses.
 	}
 
 	var scResp models.SyncCommitteeResponse
@@ -112,3 +253,238 @@ func (c *ConsensusService) GetSyncCommitteeDuties(slot uint64) ([]string, error)
 
 	return scResp.Data.Validators, nil // Return the list of validator addresses.
 }
+
+// GetBlockHeader fetches the beacon block header for a block identifier (head, finalized, a slot, or a block root).
+// It returns a pointer to a BeaconHeaderResponse and an error if any issues occur during the request or data parsing.
+func (c *ConsensusService) GetBlockHeader(ctx context.Context, blockID string) (*models.BeaconHeaderResponse, error) {
+	url := fmt.Sprintf("%s/eth/v1/beacon/headers/%s", c.endpoint, blockID)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBlockNotFound // Handle 404 response.
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "") // Handle non-200 HTTP responses.
+	}
+
+	var headerResp models.BeaconHeaderResponse
+	if err := json.NewDecoder(resp.Body).Decode(&headerResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	return &headerResp, nil // Return the beacon header response.
+}
+
+// GetBlockHeadersBySlot fetches the headers of every block the beacon node knows at a slot, canonical or not.
+// An empty result means the node has never seen a block for the slot.
+func (c *ConsensusService) GetBlockHeadersBySlot(ctx context.Context, slot uint64) (*models.BeaconHeadersResponse, error) {
+	url := fmt.Sprintf("%s/eth/v1/beacon/headers?slot=%d", c.endpoint, slot)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &models.BeaconHeadersResponse{}, nil // Some nodes answer 404 rather than an empty list.
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "headers endpoint") // Handle non-200 HTTP responses.
+	}
+
+	var headersResp models.BeaconHeadersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&headersResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	return &headersResp, nil // Return the beacon headers response.
+}
+
+// GetFinalityCheckpoints retrieves the justified and finalized checkpoints of the head state.
+// It returns a pointer to a FinalityCheckpointsResponse and an error if any issues occur during the request or data parsing.
+func (c *ConsensusService) GetFinalityCheckpoints(ctx context.Context) (*models.FinalityCheckpointsResponse, error) {
+	url := fmt.Sprintf("%s/eth/v1/beacon/states/head/finality_checkpoints", c.endpoint)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "finality checkpoints endpoint") // Handle non-200 HTTP responses.
+	}
+
+	var checkpointsResp models.FinalityCheckpointsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&checkpointsResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	return &checkpointsResp, nil // Return the finality checkpoints response.
+}
+
+// ResolveSlot converts a named block identifier ("head", "finalized" or "justified") into a slot number.
+// Head and finalized are resolved directly through the headers endpoint, while justified is resolved
+// through the root of the current justified checkpoint.
+func (c *ConsensusService) ResolveSlot(ctx context.Context, alias string) (uint64, error) {
+	var blockID string
+	switch alias {
+	case "head", "finalized":
+		blockID = alias
+	case "justified":
+		checkpoints, err := c.GetFinalityCheckpoints(ctx)
+		if err != nil {
+			return 0, err // Return an error if the checkpoints cannot be fetched.
+		}
+		blockID = checkpoints.Data.CurrentJustified.Root
+	default:
+		return 0, fmt.Errorf("unsupported slot alias %q", alias)
+	}
+
+	headerResp, err := c.GetBlockHeader(ctx, blockID)
+	if err != nil {
+		return 0, err // Return an error if the header cannot be fetched.
+	}
+	slot, err := strconv.ParseUint(headerResp.Data.Header.Message.Slot, 10, 64)
+	if err != nil {
+		return 0, err // Return an error if slot conversion fails.
+	}
+	return slot, nil // Return the resolved slot number.
+}
+
+// GetBlockConsensusReward fetches the consensus-layer reward earned by the proposer of the block at the given slot.
+// It returns a pointer to a ConsensusBlockRewardsResponse and an error if any issues occur during the request or data parsing.
+func (c *ConsensusService) GetBlockConsensusReward(ctx context.Context, slot uint64) (*models.ConsensusBlockRewardsResponse, error) {
+	url := fmt.Sprintf("%s/eth/v1/beacon/rewards/blocks/%d", c.endpoint, slot)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBlockNotFound // Handle 404 response.
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "block rewards endpoint") // Handle non-200 HTTP responses.
+	}
+
+	var rewardsResp models.ConsensusBlockRewardsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rewardsResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	return &rewardsResp, nil // Return the consensus block rewards response.
+}
+
+// GetAttestationRewards fetches the attestation rewards earned during an epoch by the given validators.
+// An empty list of validator indices requests the rewards of every active validator.
+// It returns a pointer to an AttestationRewardsResponse and an error if any issues occur during the request or data parsing.
+func (c *ConsensusService) GetAttestationRewards(ctx context.Context, epoch uint64, validators []string) (*models.AttestationRewardsResponse, error) {
+	url := fmt.Sprintf("%s/eth/v1/beacon/rewards/attestations/%d", c.endpoint, epoch)
+	if validators == nil {
+		validators = []string{}
+	}
+	body, err := json.Marshal(validators)
+	if err != nil {
+		return nil, err // Return an error if the request body cannot be encoded.
+	}
+
+	resp, err := c.post(ctx, url, body)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrAttestationRewardsNotFound // Handle 404 response.
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "attestation rewards endpoint") // Handle non-200 HTTP responses.
+	}
+
+	var rewardsResp models.AttestationRewardsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rewardsResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	return &rewardsResp, nil // Return the attestation rewards response.
+}
+
+// GetSyncCommitteeRewards fetches the rewards earned by the sync committee members for the block at the given slot.
+// It returns a pointer to a SyncCommitteeRewardsResponse and an error if any issues occur during the request or data parsing.
+func (c *ConsensusService) GetSyncCommitteeRewards(ctx context.Context, slot uint64) (*models.SyncCommitteeRewardsResponse, error) {
+	url := fmt.Sprintf("%s/eth/v1/beacon/rewards/sync_committee/%d", c.endpoint, slot)
+	resp, err := c.post(ctx, url, []byte("[]")) // An empty list requests the rewards of the whole committee.
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBlockNotFound // Handle 404 response.
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "sync committee rewards endpoint") // Handle non-200 HTTP responses.
+	}
+
+	var rewardsResp models.SyncCommitteeRewardsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rewardsResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	return &rewardsResp, nil // Return the sync committee rewards response.
+}
+
+// GetProposerDuties fetches the validators scheduled to propose in each slot of the given epoch.
+// Beacon nodes serve duties up to one epoch beyond the head; older epochs are computed from historical states.
+// It returns the duties in slot order and an error if any issues occur during the request or data parsing.
+func (c *ConsensusService) GetProposerDuties(ctx context.Context, epoch uint64) ([]models.ProposerDuty, error) {
+	url := fmt.Sprintf("%s/eth/v1/validator/duties/proposer/%d", c.endpoint, epoch)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "proposer duties endpoint") // Handle non-200 HTTP responses.
+	}
+
+	var dutiesResp models.ProposerDutiesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&dutiesResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	return dutiesResp.Data, nil // Return the proposer duties.
+}
+
+// validatorLookupBatchSize caps the number of validator indices resolved by a single request to keep URLs short.
+const validatorLookupBatchSize = 100
+
+// GetValidatorPubkeys resolves validator indices to their BLS public keys using the head state.
+// The lookups are batched, so a full sync committee is resolved with a handful of requests.
+// Returns a map from validator index to public key and an error if any issues occur during the request or data parsing.
+func (c *ConsensusService) GetValidatorPubkeys(ctx context.Context, indices []string) (map[string]string, error) {
+	pubkeys := make(map[string]string, len(indices))
+	for start := 0; start < len(indices); start += validatorLookupBatchSize {
+		end := start + validatorLookupBatchSize
+		if end > len(indices) {
+			end = len(indices)
+		}
+
+		url := fmt.Sprintf("%s/eth/v1/beacon/states/head/validators?id=%s", c.endpoint, strings.Join(indices[start:end], ","))
+		resp, err := c.get(ctx, url)
+		if err != nil {
+			return nil, err // Return an error if the HTTP request fails.
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			statusErr := newStatusError(resp, "validators endpoint")
+			resp.Body.Close()
+			return nil, statusErr // Handle non-200 HTTP responses.
+		}
+
+		var validatorsResp models.ValidatorsResponse
+		err = json.NewDecoder(resp.Body).Decode(&validatorsResp)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err // Return an error if JSON decoding fails.
+		}
+		for _, v := range validatorsResp.Data {
+			pubkeys[v.Index] = v.Validator.Pubkey
+		}
+	}
+	return pubkeys, nil // Return the public keys keyed by validator index.
+}
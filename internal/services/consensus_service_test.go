@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSyncCommitteePeriodBoundary(t *testing.T) {
+	// On mainnet a period lasts 256 epochs of 32 slots: period 1098 covers slots 8994816 to 9003007.
+	const (
+		firstOfPeriod = 1098 * 256 * 32
+		lastOfPeriod  = 1099*256*32 - 1
+		firstOfNext   = 1099 * 256 * 32
+	)
+
+	tests := []struct {
+		name       string
+		slot       uint64
+		head       uint64
+		wantPeriod uint64
+		wantState  string // The state the committee is requested from.
+		wantEpoch  string // The epoch the committee is requested for.
+	}{
+		{"first slot of period", firstOfPeriod, firstOfNext + 100, 1098, "8994816", "281088"},
+		{"last slot of period", lastOfPeriod, firstOfNext + 100, 1098, "8994816", "281088"},
+		{"first slot of next period", firstOfNext, firstOfNext + 100, 1099, "9003008", "281344"},
+		{"last slot of period at head", lastOfPeriod, lastOfPeriod, 1098, "8994816", "281088"},
+		// The committee of the next period is already known to the head state, but its first state does not exist yet.
+		{"first slot of next period ahead of head", firstOfNext, lastOfPeriod, 1099, "head", "281344"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotState, gotEpoch string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/eth/v1/beacon/headers":
+					fmt.Fprintf(w, `{"data":[{"header":{"message":{"slot":"%d"}}}]}`, tt.head)
+				case strings.HasSuffix(r.URL.Path, "/sync_committees"):
+					gotState = strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/eth/v1/beacon/states/"), "/sync_committees")
+					gotEpoch = r.URL.Query().Get("epoch")
+					json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"validators": []string{"1", "2"}}})
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+			c := NewConsensusService(server.URL, WithRetryPolicy(NoRetry))
+
+			if got := c.SyncCommitteePeriod(tt.slot); got != tt.wantPeriod {
+				t.Errorf("SyncCommitteePeriod(%d) = %d, want %d", tt.slot, got, tt.wantPeriod)
+			}
+			if _, err := c.GetSyncCommitteeDuties(context.Background(), tt.slot); err != nil {
+				t.Fatalf("GetSyncCommitteeDuties(%d): %v", tt.slot, err)
+			}
+			if gotState != tt.wantState || gotEpoch != tt.wantEpoch {
+				t.Errorf("committee requested from state %q for epoch %q, want state %q for epoch %q", gotState, gotEpoch, tt.wantState, tt.wantEpoch)
+			}
+		})
+	}
+}
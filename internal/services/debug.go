@@ -0,0 +1,78 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxDebugBodySize caps how much of each upstream request and response body is logged by the debug transport.
+const maxDebugBodySize = 2048
+
+// debugTransport logs every upstream request and its response, for diagnosing provider issues.
+// URLs are logged with their credentials masked, and bodies are truncated to maxDebugBodySize bytes.
+type debugTransport struct {
+	base    http.RoundTripper // The transport sending the requests; nil means http.DefaultTransport.
+	service string            // The service the requests belong to, e.g. "consensus".
+}
+
+// RoundTrip sends the request through the base transport and logs it together with the response.
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	attrs := []any{"service", t.service, "method", req.Method, "url", sanitizeURL(req.URL.String())}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			attrs = append(attrs, "request_body", readDebugBody(body))
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	attrs = append(attrs, "latency_ms", time.Since(start).Milliseconds())
+	if err != nil {
+		slog.InfoContext(req.Context(), "upstream call failed", append(attrs, "error", sanitizeError(err))...)
+		return nil, err
+	}
+
+	attrs = append(attrs, "status", resp.StatusCode)
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		// Read the start of the body for the log and hand the caller a body that still yields all of it.
+		// Event streams never end, so their bodies are left alone.
+		prefix, readErr := io.ReadAll(io.LimitReader(resp.Body, maxDebugBodySize+1))
+		resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(prefix), resp.Body), Closer: resp.Body}
+		attrs = append(attrs, "response_body", truncateDebugBody(prefix))
+		if readErr != nil {
+			attrs = append(attrs, "response_error", readErr)
+		}
+	}
+	slog.InfoContext(req.Context(), "upstream call", attrs...)
+	return resp, nil
+}
+
+// prefixedBody is a response body whose start has already been read for logging.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// readDebugBody reads the start of a request body for logging.
+func readDebugBody(body io.Reader) string {
+	b, _ := io.ReadAll(io.LimitReader(body, maxDebugBodySize+1))
+	return truncateDebugBody(b)
+}
+
+// truncateDebugBody returns body as a string cut to maxDebugBodySize bytes.
+func truncateDebugBody(body []byte) string {
+	if len(body) > maxDebugBodySize {
+		return strings.ToValidUTF8(string(body[:maxDebugBodySize]), "�") + "…"
+	}
+	return string(body)
+}
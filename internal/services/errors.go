@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors returned by the services when the upstream node reports that the requested data does not exist.
+// Callers should match them with errors.Is, since they may be wrapped with additional context.
+var (
+	// ErrBlockNotFound is returned when the beacon node has no block for the requested slot or block ID,
+	// typically because the slot was missed.
+	ErrBlockNotFound = errors.New("block not found")
+
+	// ErrSyncCommitteeNotFound is returned when the beacon node has no sync committee for the requested slot.
+	ErrSyncCommitteeNotFound = errors.New("sync committee duties not found for this slot")
+
+	// ErrAttestationRewardsNotFound is returned when the beacon node has no attestation rewards for the requested epoch.
+	ErrAttestationRewardsNotFound = errors.New("attestation rewards not found for this epoch")
+
+	// ErrExecutionBlockNotFound is returned when the execution client has no block, or no receipts, for the requested number.
+	ErrExecutionBlockNotFound = errors.New("block not found on execution layer")
+)
+
+// maxErrorBodySize caps how much of an upstream error response is kept in a StatusError.
+const maxErrorBodySize = 512
+
+// StatusError is returned when an upstream answers with an unexpected HTTP status code.
+// It carries the start of the response body, which usually explains provider-side failures such as
+// rejected credentials or an exhausted quota.
+type StatusError struct {
+	StatusCode int    // The HTTP status code of the response.
+	Source     string // The upstream endpoint that answered, e.g. "sync duties endpoint"; empty if not specified.
+	Body       string // The response body, truncated to maxErrorBodySize bytes.
+}
+
+// Error returns the status code, source and body of the response.
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	if e.Source != "" {
+		msg += " from " + e.Source
+	}
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+// newStatusError reads the start of an unexpected response's body and returns a StatusError describing it.
+// The caller remains responsible for closing the body.
+func newStatusError(resp *http.Response, source string) *StatusError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize+1))
+	truncated := len(body) > maxErrorBodySize
+	if truncated {
+		body = body[:maxErrorBodySize]
+	}
+	// Keep the body printable in logs even if it was cut in the middle of a character or is not text.
+	text := strings.TrimSpace(strings.ToValidUTF8(string(body), "�"))
+	if truncated {
+		text += "…"
+	}
+	return &StatusError{StatusCode: resp.StatusCode, Source: source, Body: text}
+}
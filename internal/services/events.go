@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The delays before reconnecting to the head event stream after it failed; the delay doubles up to the maximum.
+const (
+	headStreamMinBackoff = 1 * time.Second
+	headStreamMaxBackoff = 30 * time.Second
+)
+
+// headEvent represents the data of a "head" event of the beacon node's event stream.
+type headEvent struct {
+	Slot  string `json:"slot"`  // The slot of the new head block.
+	Block string `json:"block"` // The root of the new head block.
+}
+
+// SubscribeHeads subscribes to the "head" topic of the beacon node's event stream and sends the slot of every new head
+// to the returned channel. While the stream is connected, it also keeps the head slot returned by GetHeadSlot current.
+// It returns an error if the stream cannot be opened, e.g. because the node does not support it. Once subscribed, a
+// broken stream is reopened with exponential backoff. The channel is closed when ctx is done.
+func (c *ConsensusService) SubscribeHeads(ctx context.Context) (<-chan uint64, error) {
+	resp, err := c.openHeadStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	heads := make(chan uint64)
+	go c.followHeads(ctx, resp, heads)
+	return heads, nil
+}
+
+// openHeadStream connects to the head topic of the event stream.
+// The stream stays open indefinitely, so only waiting for the response headers is bound by the client's timeout.
+func (c *ConsensusService) openHeadStream(ctx context.Context) (*http.Response, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, fmt.Sprintf("%s/eth/v1/events?topics=head", c.endpoint), nil)
+	if err != nil {
+		cancel()
+		return nil, sanitizeError(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	client := &http.Client{Transport: c.client.Transport}
+	timer := time.AfterFunc(c.client.Timeout, cancel)
+	resp, err := client.Do(req)
+	timer.Stop()
+	if err != nil {
+		cancel()
+		return nil, sanitizeError(err) // Return an error if the HTTP request fails.
+	}
+	if resp.StatusCode != http.StatusOK {
+		statusErr := newStatusError(resp, "events endpoint")
+		resp.Body.Close()
+		cancel()
+		return nil, statusErr // Handle non-200 HTTP responses.
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of a streamed response when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels its request context.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// followHeads reads head events from resp and reconnects whenever the stream breaks, until ctx is done.
+func (c *ConsensusService) followHeads(ctx context.Context, resp *http.Response, heads chan<- uint64) {
+	defer close(heads)
+	for {
+		c.headSlot.follow(true)
+		err := readHeadEvents(ctx, resp.Body, func(slot uint64) bool {
+			c.headSlot.set(slot)
+			select {
+			case heads <- slot:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		resp.Body.Close()
+		c.headSlot.follow(false)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.WarnContext(ctx, "head event stream interrupted", "error", err)
+
+		// Reconnect with exponential backoff.
+		for delay := headStreamMinBackoff; ; delay = min(2*delay, headStreamMaxBackoff) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			if resp, err = c.openHeadStream(ctx); err == nil {
+				break
+			}
+			slog.WarnContext(ctx, "failed to reconnect to head event stream", "error", err)
+		}
+	}
+}
+
+// readHeadEvents parses a server-sent event stream and calls onHead with the slot of every head event.
+// It returns when the stream ends, fails, or onHead returns false.
+func readHeadEvents(ctx context.Context, body io.Reader, onHead func(slot uint64) bool) error {
+	scanner := bufio.NewScanner(body)
+	var event string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line dispatches the event collected so far.
+			if event == "head" {
+				var head headEvent
+				if err := json.Unmarshal([]byte(data.String()), &head); err != nil {
+					return fmt.Errorf("invalid head event: %w", err)
+				}
+				slot, err := strconv.ParseUint(head.Slot, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid slot %q in head event", head.Slot)
+				}
+				if !onHead(slot) {
+					return ctx.Err()
+				}
+			}
+			event = ""
+			data.Reset()
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		// Comments (lines starting with ':') are keep-alives, and other fields are not used by the beacon API.
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
@@ -5,30 +5,66 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
-	"time"
+	"sync/atomic"
 
 	"eth-rewards-api/internal/models"
+	"eth-rewards-api/utils"
+
+	"golang.org/x/sync/singleflight"
 )
 
-// ExecutionService is a struct that holds the endpoint URL and an HTTP client for making requests.
+// ExecutionService is a struct that holds the endpoint URL, an HTTP client for making requests, and the retry policy applied to them.
 type ExecutionService struct {
 	endpoint string
 	client   *http.Client
+	retry    RetryPolicy
+
+	fullTransactions bool // Whether blocks are fetched with full transaction objects rather than transaction hashes.
+
+	blocks singleflight.Group // Coalesces concurrent requests for the same execution block.
+	lastID atomic.Int64       // The id of the most recent JSON-RPC request; ids are unique for the lifetime of the service.
+
+	chainID staticValue[uint64] // The chain ID of the network, once fetched.
 }
 
 // NewExecutionService initializes a new instance of ExecutionService with a specified endpoint and a default HTTP client.
-func NewExecutionService(endpoint string) *ExecutionService {
+// Optional settings such as the retry policy can be overridden with opts.
+func NewExecutionService(endpoint string, opts ...Option) *ExecutionService {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
 	return &ExecutionService{
 		endpoint: endpoint,
 		client: &http.Client{
-			Timeout: 10 * time.Second, // Sets a timeout for HTTP requests.
+			Timeout:   o.timeout,                    // Sets a timeout for HTTP requests.
+			Transport: o.httpTransport("execution"), // A nil transport falls back to http.DefaultTransport.
 		},
+		retry:            o.retry,
+		fullTransactions: !o.transactionHashesOnly,
 	}
 }
 
+// post sends a JSON-RPC request body to the execution endpoint, retrying transient failures according to the retry policy.
+// JSON-RPC reads such as eth_getBlockByNumber are idempotent, so they are safe to send more than once.
+// The request is canceled when ctx is done.
+func (e *ExecutionService) post(ctx context.Context, body []byte) (*http.Response, error) {
+	return doWithRetry(ctx, "execution", e.client, e.retry, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+}
+
 // JSONRPCRequest represents the structure of a JSON-RPC request.
 // It includes the JSON-RPC version, method name, parameters, and an identifier.
 type JSONRPCRequest struct {
@@ -38,20 +74,53 @@ type JSONRPCRequest struct {
 	Id      int           `json:"id"`
 }
 
+// newRequest returns a JSON-RPC request for method with a fresh id, so that responses can be matched to their requests.
+func (e *ExecutionService) newRequest(method string, params ...interface{}) JSONRPCRequest {
+	return JSONRPCRequest{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params:  params,
+		Id:      int(e.lastID.Add(1)),
+	}
+}
+
+// checkResponseID returns an error if a JSON-RPC response does not answer the request with the given id.
+func checkResponseID(req JSONRPCRequest, responseID int) error {
+	if responseID != req.Id {
+		return fmt.Errorf("%s response id %d does not match request id %d", req.Method, responseID, req.Id)
+	}
+	return nil
+}
+
 // GetExecutionBlockByNumber sends a JSON-RPC request to retrieve an execution block by its number in hexadecimal format.
 // It returns a pointer to an ExecutionBlockFullResponse and an error if any issues occur during the request or data parsing.
-func (e *ExecutionService) GetExecutionBlockByNumber(blockNumberHex string) (*models.ExecutionBlockFullResponse, error) {
-	// Create a JSON-RPC request body with the method "eth_getBlockByNumber" and the block number as a parameter.
-	reqBody := JSONRPCRequest{
-		Jsonrpc: "2.0",
-		Method:  "eth_getBlockByNumber",
-		Params:  []interface{}{blockNumberHex, true},
-		Id:      1,
+// The block carries transaction hashes only if the service was created WithTransactionHashesOnly.
+// Concurrent requests for the same block share a single upstream call, so the returned block must not be modified.
+func (e *ExecutionService) GetExecutionBlockByNumber(ctx context.Context, blockNumberHex string) (*models.ExecutionBlockFullResponse, error) {
+	if !e.fullTransactions {
+		return coalesce(ctx, &e.blocks, blockNumberHex+"/hashes", func(ctx context.Context) (*models.ExecutionBlockFullResponse, error) {
+			return e.fetchExecutionBlock(ctx, blockNumberHex, false)
+		})
 	}
+	return e.GetExecutionBlockWithTransactions(ctx, blockNumberHex)
+}
+
+// GetExecutionBlockWithTransactions is like GetExecutionBlockByNumber, but always retrieves the full transaction objects.
+// Concurrent requests for the same block share a single upstream call, so the returned block must not be modified.
+func (e *ExecutionService) GetExecutionBlockWithTransactions(ctx context.Context, blockNumberHex string) (*models.ExecutionBlockFullResponse, error) {
+	return coalesce(ctx, &e.blocks, blockNumberHex, func(ctx context.Context) (*models.ExecutionBlockFullResponse, error) {
+		return e.fetchExecutionBlock(ctx, blockNumberHex, true)
+	})
+}
+
+// fetchExecutionBlock requests an execution block from the execution client, with its full transactions or their hashes only.
+func (e *ExecutionService) fetchExecutionBlock(ctx context.Context, blockNumberHex string, fullTransactions bool) (*models.ExecutionBlockFullResponse, error) {
+	// Create a JSON-RPC request body with the method "eth_getBlockByNumber" and the block number as a parameter.
+	reqBody := e.newRequest("eth_getBlockByNumber", blockNumberHex, fullTransactions)
 	// Marshal the request body into JSON format.
 	b, _ := json.Marshal(reqBody)
 	// Send a POST request to the execution endpoint with the JSON-RPC request body.
-	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(b))
+	resp, err := e.post(ctx, b)
 	if err != nil {
 		return nil, err // Return an error if the HTTP request fails.
 	}
@@ -59,7 +128,7 @@ func (e *ExecutionService) GetExecutionBlockByNumber(blockNumberHex string) (*mo
 
 	// Check if the response status code is not 200 OK.
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode) // Handle non-200 HTTP responses.
+		return nil, newStatusError(resp, "") // Handle non-200 HTTP responses.
 	}
 
 	// Decode the JSON response body into an ExecutionBlockFullResponse struct.
@@ -67,9 +136,196 @@ func (e *ExecutionService) GetExecutionBlockByNumber(blockNumberHex string) (*mo
 	if err := json.NewDecoder(resp.Body).Decode(&blockResp); err != nil {
 		return nil, err // Return an error if JSON decoding fails.
 	}
+	// Check that the response answers this request.
+	if err := checkResponseID(reqBody, blockResp.Id); err != nil {
+		return nil, err
+	}
+	// Check if the node reported an error, which would otherwise look like a missing block.
+	if blockResp.Error != nil {
+		return nil, blockResp.Error
+	}
 	// Check if the block number in the response is empty, indicating the block was not found.
 	if blockResp.Result.Number == "" {
-		return nil, fmt.Errorf("block not found on execution layer") // Handle block not found scenario.
+		return nil, ErrExecutionBlockNotFound // Handle block not found scenario.
 	}
 	return &blockResp, nil // Return the execution block response.
 }
+
+// GetBlockReceipts sends a JSON-RPC request to retrieve the receipts of every transaction in a block.
+// The returned slice is keyed by transaction index, so receipts[i] belongs to the i-th transaction of the block.
+func (e *ExecutionService) GetBlockReceipts(ctx context.Context, blockNumberHex string) ([]models.TransactionReceipt, error) {
+	// Create a JSON-RPC request body with the method "eth_getBlockReceipts" and the block number as a parameter.
+	reqBody := e.newRequest("eth_getBlockReceipts", blockNumberHex)
+	// Marshal the request body into JSON format.
+	b, _ := json.Marshal(reqBody)
+	// Send a POST request to the execution endpoint with the JSON-RPC request body.
+	resp, err := e.post(ctx, b)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	// Check if the response status code is not 200 OK.
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "") // Handle non-200 HTTP responses.
+	}
+
+	// Decode the JSON response body into a BlockReceiptsResponse struct.
+	var receiptsResp models.BlockReceiptsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&receiptsResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	// Check that the response answers this request.
+	if err := checkResponseID(reqBody, receiptsResp.Id); err != nil {
+		return nil, err
+	}
+	// Check if the node reported an error instead of the receipts.
+	if receiptsResp.Error != nil {
+		return nil, receiptsResp.Error
+	}
+	// A null result means the node does not know the block yet; an empty block has an empty list of receipts.
+	if receiptsResp.Result == nil {
+		return nil, ErrExecutionBlockNotFound
+	}
+
+	return orderReceipts(receiptsResp.Result) // Return the receipts ordered by transaction index.
+}
+
+// orderReceipts places each receipt at the position given by its transaction index.
+// It returns an error if an index is invalid, out of range, or appears twice.
+func orderReceipts(result []models.TransactionReceipt) ([]models.TransactionReceipt, error) {
+	receipts := make([]models.TransactionReceipt, len(result))
+	seen := make([]bool, len(result))
+	for _, receipt := range result {
+		quantity, err := utils.ParseHexQuantity(receipt.TransactionIndex)
+		if err != nil || !quantity.IsUint64() {
+			return nil, fmt.Errorf("invalid transaction index %q in receipt", receipt.TransactionIndex)
+		}
+		index := quantity.Uint64()
+		if index >= uint64(len(receipts)) || seen[index] {
+			return nil, fmt.Errorf("unexpected transaction index %q in receipt", receipt.TransactionIndex)
+		}
+		receipts[index] = receipt
+		seen[index] = true
+	}
+	return receipts, nil
+}
+
+// GetBalance sends a JSON-RPC request to retrieve the balance of an address, in wei, as of the given block number in hexadecimal format.
+// The block may also be a tag such as "latest". Balances of blocks older than the node's state history are only available from archive nodes.
+func (e *ExecutionService) GetBalance(ctx context.Context, address, blockNumberHex string) (*big.Int, error) {
+	// Create a JSON-RPC request body with the method "eth_getBalance" and the address and block number as parameters.
+	reqBody := e.newRequest("eth_getBalance", address, blockNumberHex)
+	// Marshal the request body into JSON format.
+	b, _ := json.Marshal(reqBody)
+	// Send a POST request to the execution endpoint with the JSON-RPC request body.
+	resp, err := e.post(ctx, b)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	// Check if the response status code is not 200 OK.
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "") // Handle non-200 HTTP responses.
+	}
+
+	// Decode the JSON response body into a BalanceResponse struct.
+	var balanceResp models.BalanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&balanceResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	// Check that the response answers this request.
+	if err := checkResponseID(reqBody, balanceResp.Id); err != nil {
+		return nil, err
+	}
+	// Check if the node reported an error instead of the balance.
+	if balanceResp.Error != nil {
+		return nil, balanceResp.Error
+	}
+	return parseHexQuantity(balanceResp.Result)
+}
+
+// parseHexQuantity parses a JSON-RPC quantity such as "0x1bc16d674ec80000" into a big.Int.
+// Zero may be encoded as "0x0" or, by some clients, as a bare "0x". A missing value is an error.
+func parseHexQuantity(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, errors.New("empty quantity in JSON-RPC result")
+	}
+	if value == "0x" {
+		return big.NewInt(0), nil
+	}
+	return utils.ParseHexQuantity(value)
+}
+
+// GetBalanceDelta returns how much the balance of an address changed, in wei, across the given block.
+// It compares the balance as of the block with the balance as of its parent.
+func (e *ExecutionService) GetBalanceDelta(ctx context.Context, address string, blockNumber uint64) (*big.Int, error) {
+	if blockNumber == 0 {
+		return nil, fmt.Errorf("block 0 has no parent")
+	}
+	before, err := e.GetBalance(ctx, address, fmt.Sprintf("0x%x", blockNumber-1))
+	if err != nil {
+		return nil, err
+	}
+	after, err := e.GetBalance(ctx, address, fmt.Sprintf("0x%x", blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return after.Sub(after, before), nil
+}
+
+// GetBlockNumber sends a JSON-RPC request to retrieve the number of the most recent block the execution client knows.
+// The call is cheap, which makes it suitable for checking that the execution endpoint is reachable.
+func (e *ExecutionService) GetBlockNumber(ctx context.Context) (uint64, error) {
+	return e.getQuantity(ctx, "eth_blockNumber")
+}
+
+// GetChainID sends a JSON-RPC request to retrieve the chain ID of the network the execution client serves, e.g. 1 for mainnet.
+// It is fetched once and then served from memory, since it is fixed for a network.
+func (e *ExecutionService) GetChainID(ctx context.Context) (uint64, error) {
+	return e.chainID.get(ctx, func(ctx context.Context) (uint64, error) {
+		return e.getQuantity(ctx, "eth_chainId")
+	})
+}
+
+// getQuantity sends a JSON-RPC request for a method without parameters whose result is a single quantity.
+func (e *ExecutionService) getQuantity(ctx context.Context, method string) (uint64, error) {
+	// Create a JSON-RPC request body with the method and no parameters.
+	reqBody := e.newRequest(method)
+	// Marshal the request body into JSON format.
+	b, _ := json.Marshal(reqBody)
+	// Send a POST request to the execution endpoint with the JSON-RPC request body.
+	resp, err := e.post(ctx, b)
+	if err != nil {
+		return 0, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	// Check if the response status code is not 200 OK.
+	if resp.StatusCode != http.StatusOK {
+		return 0, newStatusError(resp, "") // Handle non-200 HTTP responses.
+	}
+
+	// Decode the JSON response body into a QuantityResponse struct.
+	var quantityResp models.QuantityResponse
+	if err := json.NewDecoder(resp.Body).Decode(&quantityResp); err != nil {
+		return 0, err // Return an error if JSON decoding fails.
+	}
+	// Check that the response answers this request.
+	if err := checkResponseID(reqBody, quantityResp.Id); err != nil {
+		return 0, err
+	}
+	// Check if the node reported an error instead of the result.
+	if quantityResp.Error != nil {
+		return 0, quantityResp.Error
+	}
+	quantity, err := parseHexQuantity(quantityResp.Result)
+	if err != nil {
+		return 0, err
+	}
+	if !quantity.IsUint64() {
+		return 0, fmt.Errorf("%s result %s out of range", method, quantity)
+	}
+	return quantity.Uint64(), nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// headSlotCache keeps the most recently fetched head slot for a short time and coalesces concurrent fetches.
+// The head only advances once per slot, so serving it from memory for a few seconds saves an upstream request per API call.
+type headSlotCache struct {
+	ttl time.Duration // How long a fetched head slot is served from memory; 0 disables caching but keeps coalescing.
+
+	mu        sync.Mutex
+	slot      uint64
+	fetchedAt time.Time
+	call      *headSlotCall // The fetch in flight, if any.
+	following bool          // Set while a head event stream keeps slot up to date, so it is served regardless of its age.
+}
+
+// headSlotCall is a head slot fetch shared by all callers that arrive while it is in flight.
+type headSlotCall struct {
+	done chan struct{} // Closed once slot and err are set.
+	slot uint64
+	err  error
+}
+
+// get returns the cached head slot when it is fresh, and otherwise waits for a fetch shared with all concurrent callers.
+// The shared fetch is not canceled when ctx is, since other callers may still be waiting for it; get itself returns early.
+func (c *headSlotCache) get(ctx context.Context, fetch func(context.Context) (uint64, error)) (uint64, error) {
+	c.mu.Lock()
+	if !c.fetchedAt.IsZero() && (c.following || c.ttl > 0 && time.Since(c.fetchedAt) < c.ttl) {
+		slot := c.slot
+		c.mu.Unlock()
+		return slot, nil
+	}
+	call := c.call
+	if call == nil {
+		call = &headSlotCall{done: make(chan struct{})}
+		c.call = call
+		go c.fetch(context.WithoutCancel(ctx), call, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.slot, call.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// set stores a head slot announced by the head event stream. Older slots, e.g. from a stream that reconnected, are ignored.
+func (c *headSlotCache) set(slot uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if slot >= c.slot {
+		c.slot = slot
+		c.fetchedAt = time.Now()
+	}
+}
+
+// follow marks whether a head event stream is connected. While it is, the last announced head slot is always current,
+// since the head only changes with a new event; otherwise the cache falls back to fetching once the TTL has passed.
+func (c *headSlotCache) follow(following bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.following = following
+	if !following {
+		c.fetchedAt = time.Time{} // Fetch the head again rather than trusting a slot that may have gone stale while the stream was down.
+	}
+}
+
+// fetch runs the shared fetch, caching its result when it succeeds and releasing the waiting callers.
+// Like set, it never moves the head backwards: a slow fetch may finish after the head event stream announced a newer
+// slot, in which case the callers are given that slot instead.
+func (c *headSlotCache) fetch(ctx context.Context, call *headSlotCall, fetch func(context.Context) (uint64, error)) {
+	call.slot, call.err = fetch(ctx)
+
+	c.mu.Lock()
+	if call.err == nil {
+		if call.slot >= c.slot {
+			c.slot = call.slot
+		} else {
+			call.slot = c.slot
+		}
+		c.fetchedAt = time.Now()
+	}
+	c.call = nil
+	c.mu.Unlock()
+	close(call.done)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHeadSlotCacheFetchDoesNotMoveHeadBackwards(t *testing.T) {
+	c := &headSlotCache{}
+	released := make(chan struct{})
+	fetch := func(context.Context) (uint64, error) {
+		<-released
+		return 100, nil // The slot the beacon node reported before the newer head event arrived.
+	}
+
+	result := make(chan uint64)
+	go func() {
+		slot, err := c.get(context.Background(), fetch)
+		if err != nil {
+			t.Errorf("get: %v", err)
+		}
+		result <- slot
+	}()
+
+	// Wait for the fetch to be in flight, then announce a newer head before it finishes.
+	for {
+		c.mu.Lock()
+		inFlight := c.call != nil
+		c.mu.Unlock()
+		if inFlight {
+			break
+		}
+	}
+	c.set(105)
+	close(released)
+
+	if slot := <-result; slot != 105 {
+		t.Errorf("get returned slot %d, want 105", slot)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.slot != 105 {
+		t.Errorf("cached slot %d, want 105", c.slot)
+	}
+}
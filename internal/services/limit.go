@@ -0,0 +1,54 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// limitTransport bounds the number of upstream requests in flight, from sending the request until its response body is closed.
+// Sharing the semaphore between the services bounds their requests together, protecting a provider quota they share.
+type limitTransport struct {
+	base  http.RoundTripper // The transport sending the requests; nil means http.DefaultTransport.
+	limit *semaphore.Weighted
+}
+
+// RoundTrip waits for a free slot, giving up when the request's context is done, and sends the request through the base transport.
+// The slot is released once the response body is closed, or right away if the request fails.
+// Event stream subscriptions stay open for the life of the service, so they do not take a slot.
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Header.Get("Accept") == "text/event-stream" {
+		return base.RoundTrip(req)
+	}
+
+	if err := t.limit.Acquire(req.Context(), 1); err != nil {
+		return nil, err
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		t.limit.Release(1)
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: func() { t.limit.Release(1) }}
+	return resp, nil
+}
+
+// releaseOnClose is a response body that releases its concurrency slot when it is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+// Close closes the body and releases the slot, once.
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
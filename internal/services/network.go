@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// NetworkConfig holds the beacon chain parameters that differ between Ethereum networks.
+type NetworkConfig struct {
+	Name             string // The name of the network, e.g. "mainnet".
+	GenesisTime      int64  // The Unix time of the beacon chain genesis.
+	SlotsPerEpoch    uint64 // The number of slots in an epoch.
+	SecondsPerSlot   uint64 // The duration of a slot in seconds.
+	SyncPeriodEpochs uint64 // The number of epochs a sync committee serves before it rotates.
+	MergeSlot        uint64 // The first slot whose block carries an execution payload.
+	ChainID          uint64 // The chain ID of the execution layer, as returned by eth_chainId.
+}
+
+// Networks lists the configuration of every network the service can be pointed at, keyed by name.
+var Networks = map[string]NetworkConfig{
+	"mainnet": {
+		Name:             "mainnet",
+		GenesisTime:      MAINNET_GENESIS_TIME,
+		SlotsPerEpoch:    SLOTS_PER_EPOCH,
+		SecondsPerSlot:   SECONDS_PER_SLOT,
+		SyncPeriodEpochs: EPOCHS_PER_SYNC_COMMITTEE_PERIOD,
+		MergeSlot:        4700013,
+		ChainID:          1,
+	},
+	"holesky": {
+		Name:             "holesky",
+		GenesisTime:      1695902400,
+		SlotsPerEpoch:    32,
+		SecondsPerSlot:   12,
+		SyncPeriodEpochs: EPOCHS_PER_SYNC_COMMITTEE_PERIOD,
+		MergeSlot:        0, // Holesky launched with the merge already in effect.
+		ChainID:          17000,
+	},
+	"sepolia": {
+		Name:             "sepolia",
+		GenesisTime:      1655733600,
+		SlotsPerEpoch:    32,
+		SecondsPerSlot:   12,
+		SyncPeriodEpochs: EPOCHS_PER_SYNC_COMMITTEE_PERIOD,
+		MergeSlot:        115193,
+		ChainID:          11155111,
+	},
+}
+
+// LookupNetwork returns the configuration of the named network.
+// It returns an error if the network is not known.
+func LookupNetwork(name string) (NetworkConfig, error) {
+	network, ok := Networks[name]
+	if !ok {
+		return NetworkConfig{}, fmt.Errorf("unknown network %q", name)
+	}
+	return network, nil
+}
+
+// Genesis returns the time of slot 0 on the network.
+func (n NetworkConfig) Genesis() time.Time {
+	return time.Unix(n.GenesisTime, 0)
+}
+
+// IsPreMerge reports whether a slot lies before the merge, when blocks carried no execution payload and earned no fees.
+func (n NetworkConfig) IsPreMerge(slot uint64) bool {
+	return slot < n.MergeSlot
+}
+
+// WithSpec returns the network with the epoch length, slot duration and sync committee period length reported in the
+// beacon node's spec, so that networks with other constants than their defaults are served correctly.
+// It returns an error if the spec lacks one of the constants or reports zero for it.
+func (n NetworkConfig) WithSpec(spec Spec) (NetworkConfig, error) {
+	for name, field := range map[string]*uint64{
+		"SLOTS_PER_EPOCH":                  &n.SlotsPerEpoch,
+		"SECONDS_PER_SLOT":                 &n.SecondsPerSlot,
+		"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": &n.SyncPeriodEpochs,
+	} {
+		value, err := spec.Uint(name)
+		if err != nil {
+			return NetworkConfig{}, err
+		}
+		if value == 0 {
+			return NetworkConfig{}, fmt.Errorf("spec constant %s must not be zero", name)
+		}
+		*field = value
+	}
+	return n, nil
+}
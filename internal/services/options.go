@@ -0,0 +1,129 @@
+package services
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// Option configures optional behaviour of the ConsensusService, ExecutionService and RelayService.
+type Option func(*options)
+
+// options holds the optional settings shared by the services.
+type options struct {
+	retry     RetryPolicy
+	network   NetworkConfig
+	transport http.RoundTripper
+	timeout   time.Duration
+	debug     bool
+	limit     *semaphore.Weighted
+	userAgent string
+
+	transactionHashesOnly bool
+
+	headSlotTTL time.Duration
+}
+
+// defaultOptions returns the settings used when no Option overrides them.
+func defaultOptions() options {
+	return options{
+		retry:   DefaultRetryPolicy,
+		network: Networks["mainnet"],
+		timeout: 10 * time.Second,
+
+		headSlotTTL: 4 * time.Second,
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry transient upstream failures.
+// Tests can pass NoRetry to disable retries and their delays.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = policy
+	}
+}
+
+// WithNetwork sets the network whose genesis time, epoch length and slot duration the consensus service uses.
+// It defaults to mainnet.
+func WithNetwork(network NetworkConfig) Option {
+	return func(o *options) {
+		o.network = network
+	}
+}
+
+// WithTransport sets the transport the service sends its HTTP requests through.
+// Passing the same transport to several services lets them share one connection pool.
+// It defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout sets the time limit for each upstream request, including reading its response body.
+// It defaults to 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithUpstreamDebug enables logging the URL, body, status and start of the response of every upstream request.
+// URLs are logged with their API keys masked. It is off by default, since the logs are large and may contain sensitive data.
+func WithUpstreamDebug(enabled bool) Option {
+	return func(o *options) {
+		o.debug = enabled
+	}
+}
+
+// WithConcurrencyLimit bounds the number of upstream requests the service has in flight by the given semaphore.
+// Passing the same semaphore to several services bounds their requests together. Requests wait for a free slot
+// until their context is done. By default the number of requests is not limited.
+func WithConcurrencyLimit(limit *semaphore.Weighted) Option {
+	return func(o *options) {
+		o.limit = limit
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every upstream request. By default Go's generic one is sent.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// httpTransport returns the transport a service sends its requests through, wrapped to set the User-Agent header,
+// to enforce the concurrency limit and for debug logging when they are enabled.
+func (o options) httpTransport(service string) http.RoundTripper {
+	transport := o.transport
+	if o.userAgent != "" {
+		transport = &userAgentTransport{base: transport, userAgent: o.userAgent}
+	}
+	if o.limit != nil {
+		transport = &limitTransport{base: transport, limit: o.limit}
+	}
+	if o.debug {
+		transport = &debugTransport{base: transport, service: service}
+	}
+	return transport
+}
+
+// WithHeadSlotTTL sets how long the consensus service serves a fetched head slot from memory.
+// It should stay below the slot duration so the head never lags by more than a slot; 0 disables caching.
+// It defaults to 4 seconds.
+func WithHeadSlotTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.headSlotTTL = ttl
+	}
+}
+
+// WithTransactionHashesOnly makes the execution service fetch blocks with the hashes of their transactions rather than
+// the full transaction objects, which for large blocks saves most of the payload. The reward math then reads the type,
+// sender, recipient and effective gas price of each transaction from its receipt, and the full transactions are only
+// fetched when a calculation needs fields the receipts lack. It is off by default.
+func WithTransactionHashesOnly(enabled bool) Option {
+	return func(o *options) {
+		o.transactionHashesOnly = enabled
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces the credentials masked by sanitizeURL.
+const redacted = "REDACTED"
+
+// minKeySegmentLength is the length from which a URL path segment is treated as an embedded API key.
+// Beacon API path segments are short words, numbers and 0x-prefixed roots, while provider keys are long random tokens.
+const minKeySegmentLength = 20
+
+// secretQueryParams lists the query parameters whose values are credentials, in lower case.
+var secretQueryParams = map[string]bool{
+	"key":          true,
+	"apikey":       true,
+	"api_key":      true,
+	"token":        true,
+	"access_token": true,
+	"auth":         true,
+}
+
+// SanitizeURL masks the credentials a provider URL may embed, as sanitizeURL does, for callers outside the package
+// that print configured URLs.
+func SanitizeURL(rawURL string) string {
+	return sanitizeURL(rawURL)
+}
+
+// sanitizeURL masks the credentials a provider URL may embed so that it can be logged safely: the password of the
+// user info, the values of key and token query parameters, and path segments that look like API keys, such as the
+// token in "https://example.quiknode.pro/<token>/". A URL that cannot be parsed is redacted entirely.
+func sanitizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+	}
+
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if isKeySegment(segment) {
+			segments[i] = redacted
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for name := range query {
+			if secretQueryParams[strings.ToLower(name)] {
+				query.Set(name, redacted)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// sanitizeError masks the credentials in the URL quoted by an error of the HTTP client, such as
+// `Get "https://example.quiknode.pro/<token>/eth/v1/beacon/headers": dial tcp: ...`, and returns the error.
+// Other errors are returned unchanged.
+func sanitizeError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = sanitizeURL(urlErr.URL)
+	}
+	return err
+}
+
+// isKeySegment reports whether a URL path segment looks like an API key: a long token of letters, digits, '-' and '_'
+// that is neither a number nor a 0x-prefixed hex value such as a block root.
+func isKeySegment(segment string) bool {
+	if len(segment) < minKeySegmentLength || strings.HasPrefix(segment, "0x") {
+		return false
+	}
+	digitsOnly := true
+	for _, r := range segment {
+		switch {
+		case r >= '0' && r <= '9':
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-', r == '_':
+			digitsOnly = false
+		default:
+			return false
+		}
+	}
+	return !digitsOnly
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"eth-rewards-api/internal/models"
+)
+
+// ErrPayloadNotDelivered is returned when none of the configured relays delivered a payload for the requested slot.
+var ErrPayloadNotDelivered = errors.New("no relay delivered a payload for the slot")
+
+// RelayService is a struct that holds the data API URLs of MEV-Boost relays, an HTTP client for making requests,
+// and the retry policy applied to them.
+type RelayService struct {
+	endpoints []string
+	client    *http.Client
+	retry     RetryPolicy
+}
+
+// NewRelayService initializes a new instance of RelayService querying the relays at the given URLs.
+// Optional settings such as the retry policy can be overridden with opts.
+func NewRelayService(endpoints []string, opts ...Option) *RelayService {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	trimmed := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		trimmed[i] = strings.TrimSuffix(endpoint, "/")
+	}
+	return &RelayService{
+		endpoints: trimmed,
+		client: &http.Client{
+			Timeout:   o.timeout,                // Sets a timeout for HTTP requests.
+			Transport: o.httpTransport("relay"), // A nil transport falls back to http.DefaultTransport.
+		},
+		retry: o.retry,
+	}
+}
+
+// DeliveredPayload describes the payload MEV-Boost relays delivered to the proposer of a slot.
+type DeliveredPayload struct {
+	models.BidTrace
+	Relays []string // The hosts of the relays that delivered the payload, in the configured order.
+}
+
+// GetDeliveredPayload asks every configured relay, in parallel, which payload it delivered for the slot.
+// Relays delivering the same block are reported together; when relays disagree, the block of the first relay in the
+// configured order wins. ErrPayloadNotDelivered is returned when every relay answered without a delivery, and the
+// relays' errors when some could not be asked and none reported a delivery.
+func (r *RelayService) GetDeliveredPayload(ctx context.Context, slot uint64) (*DeliveredPayload, error) {
+	traces := make([]*models.BidTrace, len(r.endpoints))
+	errs := make([]error, len(r.endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range r.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			traces[i], errs[i] = r.fetchDeliveredPayload(ctx, endpoint, slot)
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	var delivered *DeliveredPayload
+	for i, trace := range traces {
+		if trace == nil {
+			continue
+		}
+		if delivered == nil {
+			delivered = &DeliveredPayload{BidTrace: *trace}
+		}
+		if strings.EqualFold(trace.BlockHash, delivered.BlockHash) {
+			delivered.Relays = append(delivered.Relays, relayName(r.endpoints[i]))
+		}
+	}
+	if delivered != nil {
+		return delivered, nil
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return nil, ErrPayloadNotDelivered
+}
+
+// fetchDeliveredPayload requests the payload a single relay delivered for the slot.
+// It returns nil without an error when the relay delivered no payload for the slot.
+func (r *RelayService) fetchDeliveredPayload(ctx context.Context, endpoint string, slot uint64) (*models.BidTrace, error) {
+	url := fmt.Sprintf("%s/relay/v1/data/bidtraces/proposer_payload_delivered?slot=%d", endpoint, slot)
+	resp, err := doWithRetry(ctx, "relay", r.client, r.retry, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	})
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, relayName(endpoint)) // Handle non-200 HTTP responses.
+	}
+
+	var traces []models.BidTrace
+	if err := json.NewDecoder(resp.Body).Decode(&traces); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	for _, trace := range traces {
+		if trace.Slot == fmt.Sprint(slot) {
+			return &trace, nil
+		}
+	}
+	return nil, nil // The relay delivered no payload for the slot.
+}
+
+// relayName returns the host of a relay URL, which identifies the relay without the credentials some URLs embed.
+func relayName(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "relay"
+	}
+	return u.Host
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eth-rewards-api/internal/metrics"
+)
+
+// RetryPolicy controls how upstream requests are retried after transient failures.
+type RetryPolicy struct {
+	MaxAttempts int           // The total number of attempts, including the first one. Values below 1 mean a single attempt.
+	BaseDelay   time.Duration // The delay before the first retry; it doubles with every further retry.
+	MaxDelay    time.Duration // The upper bound for a single delay, including delays requested via Retry-After.
+	Jitter      float64       // The fraction (0-1) of each delay that is randomized to spread out concurrent retries.
+}
+
+// DefaultRetryPolicy is the policy used by the services unless another one is injected with WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      0.2,
+}
+
+// NoRetry disables retries so that every request is attempted exactly once.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// isRetryableStatus reports whether a response status indicates a transient upstream failure worth retrying.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// backoff returns the delay before the given retry (1 for the first retry), honoring a Retry-After header when present.
+func (p RetryPolicy) backoff(retry int, resp *http.Response) time.Duration {
+	// Double the base delay for every further retry, stopping once it reaches MaxDelay, or before it would overflow
+	// when there is no MaxDelay, rather than shifting it by the retry count.
+	delay := p.BaseDelay
+	for i := 1; i < retry; i++ {
+		if delay <= 0 || delay > math.MaxInt64/2 || p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			delay = retryAfter
+		}
+	}
+	if p.Jitter > 0 && delay > 0 && delay <= math.MaxInt64/2 {
+		delay += time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+// A date in the past asks for no delay at all.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(t); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// doWithRetry sends the request built by newRequest, retrying network errors, 429 and 5xx responses according to the policy.
+// newRequest is called for every attempt so that request bodies can be sent again.
+// The response of the last attempt is returned as-is, leaving non-200 handling to the caller.
+// Waiting between attempts stops early with the context's error when ctx is done.
+// Every attempt is recorded in the upstream metrics under the given service name.
+func doWithRetry(ctx context.Context, service string, client *http.Client, policy RetryPolicy, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, sanitizeError(err)
+		}
+
+		resp, err := client.Do(req)
+		metrics.ObserveUpstream(service, resp, err)
+		err = sanitizeError(err) // Errors of the HTTP client quote the URL, which may embed an API key.
+		if attempt == attempts || ctx.Err() != nil {
+			return resp, err // Give up and hand the last outcome to the caller.
+		}
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil // Return successful and non-transient responses immediately.
+		}
+
+		delay := policy.backoff(attempt, resp)
+		logRetry(ctx, service, attempt, delay, resp, err)
+		if resp != nil {
+			// Drain the body so the connection can be reused by the next attempt.
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// logRetry writes a warning describing the failed upstream attempt that is about to be retried.
+// The record is logged with ctx so that it carries the ID of the request being served.
+func logRetry(ctx context.Context, service string, attempt int, delay time.Duration, resp *http.Response, err error) {
+	attrs := []any{"service", service, "attempt", attempt, "delay", delay.String()}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	} else {
+		attrs = append(attrs, "status", resp.StatusCode)
+	}
+	slog.WarnContext(ctx, "retrying upstream request", attrs...)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		want   time.Duration
+	}{
+		{"first retry", RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}, 1, 200 * time.Millisecond},
+		{"doubles per retry", RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}, 3, 800 * time.Millisecond},
+		{"capped at max delay", RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}, 10, 5 * time.Second},
+		{"large retry capped at max delay", RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}, 100, 5 * time.Second},
+		{"large retry without max delay stays positive", RetryPolicy{BaseDelay: time.Second}, 100, time.Second << 33},
+		{"zero base delay", RetryPolicy{MaxDelay: 5 * time.Second}, 5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.backoff(tt.retry, nil); got != tt.want {
+				t.Errorf("backoff(%d) = %s, want %s", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoffHonorsRetryAfter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+	}{
+		{"seconds", "2", 2 * time.Second},
+		{"seconds capped at max delay", "60", 5 * time.Second},
+		{"date in the past", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+		{"invalid falls back to backoff", "soon", 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{"Retry-After": []string{tt.retryAfter}}}
+			if got := policy.backoff(1, resp); got != tt.want {
+				t.Errorf("backoff with Retry-After %q = %s, want %s", tt.retryAfter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"empty", "", 0, false},
+		{"seconds", "3", 3 * time.Second, true},
+		{"zero seconds", "0", 0, true},
+		{"negative seconds", "-1", 0, false},
+		{"date in the past", "Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+		{"garbage", "later", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"eth-rewards-api/internal/models"
+)
+
+// Spec holds the configuration constants a beacon node reports on /eth/v1/config/spec, keyed by name,
+// e.g. "SECONDS_PER_SLOT". Constants whose values are not strings, such as lists, are left out.
+type Spec map[string]string
+
+// Uint returns the named constant as an integer.
+// It returns an error if the beacon node did not report the constant or its value is not an unsigned integer.
+func (s Spec) Uint(name string) (uint64, error) {
+	value, ok := s[name]
+	if !ok {
+		return 0, fmt.Errorf("spec constant %s not reported", name)
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid spec constant %s %q", name, value)
+	}
+	return n, nil
+}
+
+// GetSpec retrieves the configuration constants of the network the beacon node serves.
+// They are fetched once and then served from memory, since they are fixed for a network.
+func (c *ConsensusService) GetSpec(ctx context.Context) (Spec, error) {
+	return c.spec.get(ctx, c.fetchSpec)
+}
+
+// fetchSpec requests the configuration constants from the beacon node.
+func (c *ConsensusService) fetchSpec(ctx context.Context) (Spec, error) {
+	url := fmt.Sprintf("%s/eth/v1/config/spec", c.endpoint)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "spec endpoint") // Handle non-200 HTTP responses.
+	}
+
+	var specResp models.SpecResponse
+	if err := json.NewDecoder(resp.Body).Decode(&specResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	spec := make(Spec, len(specResp.Data))
+	for name, raw := range specResp.Data {
+		var value string
+		if json.Unmarshal(raw, &value) == nil {
+			spec[name] = value
+		}
+	}
+	return spec, nil
+}
+
+// GetGenesis retrieves the genesis of the beacon chain the beacon node serves.
+// It is fetched once and then served from memory, since it never changes.
+func (c *ConsensusService) GetGenesis(ctx context.Context) (*models.GenesisResponse, error) {
+	return c.genesis.get(ctx, c.fetchGenesis)
+}
+
+// fetchGenesis requests the genesis of the beacon chain from the beacon node.
+func (c *ConsensusService) fetchGenesis(ctx context.Context) (*models.GenesisResponse, error) {
+	url := fmt.Sprintf("%s/eth/v1/beacon/genesis", c.endpoint)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err // Return an error if the HTTP request fails.
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "genesis endpoint") // Handle non-200 HTTP responses.
+	}
+
+	var genesisResp models.GenesisResponse
+	if err := json.NewDecoder(resp.Body).Decode(&genesisResp); err != nil {
+		return nil, err // Return an error if JSON decoding fails.
+	}
+	return &genesisResp, nil // Return the genesis response.
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// staticValue holds a value that never changes for an endpoint, such as its chain ID or genesis, once it has been fetched.
+// Failed fetches are not remembered, so they are retried by the next caller.
+type staticValue[T any] struct {
+	mu      sync.Mutex
+	value   T
+	fetched bool
+}
+
+// get returns the value, calling fetch for it unless an earlier call succeeded.
+// Concurrent callers wait for a fetch in progress rather than starting their own.
+func (s *staticValue[T]) get(ctx context.Context, fetch func(context.Context) (T, error)) (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fetched {
+		return s.value, nil
+	}
+	value, err := fetch(ctx)
+	if err != nil {
+		return value, err
+	}
+	s.value, s.fetched = value, true
+	return value, nil
+}
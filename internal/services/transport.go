@@ -0,0 +1,35 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig controls the connection pool of the HTTP transport shared by the services.
+type TransportConfig struct {
+	MaxIdleConns        int           // The maximum number of idle connections kept across all hosts.
+	MaxIdleConnsPerHost int           // The maximum number of idle connections kept per host.
+	MaxConnsPerHost     int           // The maximum number of connections per host, including active ones; 0 means no limit.
+	IdleConnTimeout     time.Duration // How long an idle connection is kept before it is closed.
+}
+
+// NewTransport returns an HTTP transport with keep-alives and a connection pool sized according to cfg.
+// Unlike http.DefaultTransport, which keeps only 2 idle connections per host, it lets concurrent batch workers reuse their connections.
+// The transport is safe for concurrent use and is meant to be shared by all services talking to the upstream nodes.
+func NewTransport(cfg TransportConfig) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
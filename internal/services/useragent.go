@@ -0,0 +1,21 @@
+package services
+
+import "net/http"
+
+// userAgentTransport sets the User-Agent header of every upstream request, so providers can attribute the traffic.
+type userAgentTransport struct {
+	base      http.RoundTripper // The transport sending the requests; nil means http.DefaultTransport.
+	userAgent string
+}
+
+// RoundTrip sends a copy of the request carrying the User-Agent header through the base transport.
+// The request itself is left untouched, as the http.RoundTripper contract requires.
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,42 @@
+// The `version` package describes the running build. Commit and BuildTime are set at build time with -ldflags, e.g.
+//
+//	go build -ldflags "-X eth-rewards-api/internal/version.Commit=$(git rev-parse HEAD) -X eth-rewards-api/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+
+package version
+
+import "runtime"
+
+// Commit is the git commit the binary was built from.
+var Commit = "unknown"
+
+// BuildTime is the time the binary was built, in RFC 3339 format.
+var BuildTime = "unknown"
+
+// UserAgent returns the default User-Agent of upstream requests: "eth-rewards-api/" followed by the short commit,
+// or just "eth-rewards-api" when the commit was not set at build time.
+func UserAgent() string {
+	if Commit == "unknown" {
+		return "eth-rewards-api"
+	}
+	commit := Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	return "eth-rewards-api/" + commit
+}
+
+// Info describes the running build.
+type Info struct {
+	Commit    string `json:"commit"`     // The git commit the binary was built from.
+	BuildTime string `json:"build_time"` // The time the binary was built.
+	GoVersion string `json:"go_version"` // The version of Go the binary was compiled with.
+}
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	return Info{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
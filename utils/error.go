@@ -6,9 +6,54 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Machine-readable error codes returned in the code field of error responses.
+// Clients should switch on the code rather than on the human-readable message, which may be reworded.
+const (
+	CodeInvalidParameter = "INVALID_PARAMETER" // A path parameter, query parameter or request body is malformed or out of range.
+	CodeInvalidInput     = "INVALID_INPUT"     // A slot, number, epoch, period or hex value could not be parsed; details name the offending value.
+	CodeSlotInFuture     = "SLOT_IN_FUTURE"    // The requested slot lies beyond the current head slot.
+	CodeEpochInFuture    = "EPOCH_IN_FUTURE"   // The requested epoch lies beyond the epoch of the current head slot.
+	CodePeriodInFuture   = "PERIOD_IN_FUTURE"  // The requested sync committee period lies more than one period beyond the head's.
+	CodePreMergeSlot     = "PRE_MERGE_SLOT"    // The requested slot lies before the merge, so its block carries no execution payload.
+	CodeSlotMissed       = "SLOT_MISSED"       // No block was proposed in the requested slot.
+	CodeSlotOrphaned     = "SLOT_ORPHANED"     // A block was proposed in the requested slot but reorged out of the canonical chain.
+	CodePreGenesis       = "PRE_GENESIS"       // The network has not reached its genesis yet, so no slot holds a block.
+	CodeNotFound         = "NOT_FOUND"         // The requested data does not exist upstream.
+	CodeUnauthorized     = "UNAUTHORIZED"      // The API key is missing or invalid.
+	CodeOverrideDenied   = "OVERRIDE_DENIED"   // The request selects an upstream endpoint while overrides are disabled, without the override token, or by an unknown alias.
+	CodeRateLimited      = "RATE_LIMITED"      // The client exceeded its request rate; retry after the Retry-After delay.
+	CodeUpstreamError    = "UPSTREAM_ERROR"    // The beacon node or execution client failed or returned an unexpected response.
+	CodeUpstreamTimeout  = "UPSTREAM_TIMEOUT"  // The beacon node or execution client did not answer in time; the request may be retried.
+	CodeUnavailable      = "UNAVAILABLE"       // The service cannot currently serve requests.
+	CodeInternalError    = "INTERNAL_ERROR"    // An unexpected error occurred while processing the request.
+)
+
+// ErrorResponse is the JSON body of every error response.
+type ErrorResponse struct {
+	Code    string      `json:"code"`              // The machine-readable error code, one of the Code constants.
+	Message string      `json:"message"`           // The human-readable description of the error.
+	Details interface{} `json:"details,omitempty"` // Additional structured information about the error, if any.
+}
+
+// RespondError sends an error response with the given status code, error code and message.
+func RespondError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, ErrorResponse{Code: code, Message: message})
+}
+
+// RespondErrorWithDetails sends an error response that carries additional structured details.
+func RespondErrorWithDetails(c *gin.Context, status int, code, message string, details interface{}) {
+	c.JSON(status, ErrorResponse{Code: code, Message: message, Details: details})
+}
+
+// AbortWithError sends an error response and stops the remaining handlers from running.
+// It is meant for middleware that rejects requests before they reach the route handler.
+func AbortWithError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, ErrorResponse{Code: code, Message: message})
+}
+
 // HandleInternalServerError is a utility function that sends a JSON response with a 500 Internal Server Error status code.
 // It takes a gin.Context object and a message string as parameters.
-// The function constructs a JSON response with an "error" key and the provided message as its value.
+// The function constructs an error response with the INTERNAL_ERROR code and the provided message.
 func HandleInternalServerError(c *gin.Context, message string) {
-	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	RespondError(c, http.StatusInternalServerError, CodeInternalError, message)
 }
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// InputError describes a client-supplied or upstream value that could not be parsed.
+// It carries the offending value so that the error response can point at it.
+type InputError struct {
+	Value  string // The value as it was received.
+	Reason string // Why the value was rejected.
+}
+
+// Error returns the offending value together with the reason it was rejected.
+func (e *InputError) Error() string {
+	return fmt.Sprintf("invalid input %q: %s", e.Value, e.Reason)
+}
+
+// NormalizeHex trims surrounding whitespace from a 0x-prefixed hex string, accepting an uppercase 0X prefix,
+// and returns it with a lowercase 0x prefix and lowercase digits. At least one hex digit must follow the prefix.
+func NormalizeHex(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) < 2 || (trimmed[:2] != "0x" && trimmed[:2] != "0X") {
+		return "", &InputError{Value: value, Reason: "missing 0x prefix"}
+	}
+	digits := trimmed[2:]
+	if digits == "" {
+		return "", &InputError{Value: value, Reason: "no hex digits after the 0x prefix"}
+	}
+	for _, r := range digits {
+		if !isHexDigit(r) {
+			return "", &InputError{Value: value, Reason: fmt.Sprintf("invalid hex digit %q", r)}
+		}
+	}
+	return "0x" + strings.ToLower(digits), nil
+}
+
+// ParseHexQuantity parses a 0x-prefixed hex quantity such as "0x1bc16d674ec80000" into a big.Int.
+// Quantities may have an odd number of digits, e.g. "0x1".
+func ParseHexQuantity(value string) (*big.Int, error) {
+	normalized, err := NormalizeHex(value)
+	if err != nil {
+		return nil, err
+	}
+	quantity, _ := new(big.Int).SetString(normalized[2:], 16) // The digits were validated by NormalizeHex.
+	return quantity, nil
+}
+
+// ParseHexData normalizes 0x-prefixed hex data such as a block root. The data must consist of whole bytes and,
+// when size is positive, be exactly size bytes long.
+func ParseHexData(value string, size int) (string, error) {
+	normalized, err := NormalizeHex(value)
+	if err != nil {
+		return "", err
+	}
+	digits := len(normalized) - 2
+	if digits%2 != 0 {
+		return "", &InputError{Value: value, Reason: "odd number of hex digits"}
+	}
+	if size > 0 && digits != 2*size {
+		return "", &InputError{Value: value, Reason: fmt.Sprintf("must be %d bytes", size)}
+	}
+	return normalized, nil
+}
+
+// ParseUint parses a non-negative integer such as a slot or block number, given in decimal or as 0x-prefixed hex.
+// Surrounding whitespace is ignored.
+func ParseUint(value string) (uint64, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return 0, &InputError{Value: value, Reason: "empty value"}
+	}
+
+	digits, base := trimmed, 10
+	if len(trimmed) >= 2 && (trimmed[:2] == "0x" || trimmed[:2] == "0X") {
+		normalized, err := NormalizeHex(trimmed)
+		if err != nil {
+			return 0, &InputError{Value: value, Reason: err.(*InputError).Reason}
+		}
+		digits, base = normalized[2:], 16
+	}
+
+	n, err := strconv.ParseUint(digits, base, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, &InputError{Value: value, Reason: "out of range"}
+	} else if err != nil {
+		return 0, &InputError{Value: value, Reason: "not a decimal or 0x-prefixed hex number"}
+	}
+	return n, nil
+}
+
+// isHexDigit reports whether r is a hexadecimal digit in either case.
+func isHexDigit(r rune) bool {
+	return ('0' <= r && r <= '9') || ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F')
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+// checkInputError fails the test unless err is an *InputError carrying the original value and the wanted reason.
+func checkInputError(t *testing.T, err error, value, reason string) {
+	t.Helper()
+	var inputErr *InputError
+	if !errors.As(err, &inputErr) {
+		t.Errorf("%q: got error %v, want an *InputError", value, err)
+		return
+	}
+	if inputErr.Value != value || inputErr.Reason != reason {
+		t.Errorf("%q: got value %q and reason %q, want value %q and reason %q", value, inputErr.Value, inputErr.Reason, value, reason)
+	}
+}
+
+func TestNormalizeHex(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		reason string // The reason of the error, if the value is rejected.
+	}{
+		{value: "0xabc", want: "0xabc"},
+		{value: "0XABC", want: "0xabc"},
+		{value: "0xAbC", want: "0xabc"},
+		{value: "0x1", want: "0x1"}, // An odd number of digits is fine for quantities.
+		{value: "  0x1f\t", want: "0x1f"},
+		{value: "", reason: "missing 0x prefix"},
+		{value: "   ", reason: "missing 0x prefix"},
+		{value: "abc", reason: "missing 0x prefix"},
+		{value: "0x", reason: "no hex digits after the 0x prefix"},
+		{value: "0xg1", reason: `invalid hex digit 'g'`},
+		{value: "0x-1", reason: `invalid hex digit '-'`},
+		{value: "0x 1", reason: `invalid hex digit ' '`},
+		{value: "+0x1", reason: "missing 0x prefix"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeHex(tt.value)
+		if tt.reason != "" {
+			checkInputError(t, err, tt.value, tt.reason)
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeHex(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseUint(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   uint64
+		reason string // The reason of the error, if the value is rejected.
+	}{
+		{value: "0", want: 0},
+		{value: "9000000", want: 9000000},
+		{value: " 42 ", want: 42},
+		{value: "0x10", want: 16},
+		{value: "0X10", want: 16},
+		{value: "0x1", want: 1},
+		{value: "18446744073709551615", want: 1<<64 - 1},
+		{value: "0xffffffffffffffff", want: 1<<64 - 1},
+		{value: "", reason: "empty value"},
+		{value: " ", reason: "empty value"},
+		{value: "18446744073709551616", reason: "out of range"},
+		{value: "0x10000000000000000", reason: "out of range"},
+		{value: "-1", reason: "not a decimal or 0x-prefixed hex number"},
+		{value: "+1", reason: "not a decimal or 0x-prefixed hex number"},
+		{value: "1.5", reason: "not a decimal or 0x-prefixed hex number"},
+		{value: "1e3", reason: "not a decimal or 0x-prefixed hex number"},
+		{value: "abc", reason: "not a decimal or 0x-prefixed hex number"},
+		{value: "0x", reason: "no hex digits after the 0x prefix"},
+		{value: "0xz", reason: `invalid hex digit 'z'`},
+	}
+	for _, tt := range tests {
+		got, err := ParseUint(tt.value)
+		if tt.reason != "" {
+			checkInputError(t, err, tt.value, tt.reason)
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseUint(%q) = %d, %v, want %d", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseHexData(t *testing.T) {
+	tests := []struct {
+		value  string
+		size   int
+		want   string
+		reason string // The reason of the error, if the value is rejected.
+	}{
+		{value: "0xAABB", size: 0, want: "0xaabb"},
+		{value: "0XAABB", size: 2, want: "0xaabb"},
+		{value: " 0x00 ", size: 1, want: "0x00"},
+		{value: "0xabc", size: 0, reason: "odd number of hex digits"},
+		{value: "0xaabb", size: 3, reason: "must be 3 bytes"},
+		{value: "0xaabbcc", size: 2, reason: "must be 2 bytes"},
+		{value: "", size: 0, reason: "missing 0x prefix"},
+		{value: "0x", size: 0, reason: "no hex digits after the 0x prefix"},
+		{value: "aabb", size: 2, reason: "missing 0x prefix"},
+		{value: "0x-a", size: 0, reason: `invalid hex digit '-'`},
+	}
+	for _, tt := range tests {
+		got, err := ParseHexData(tt.value, tt.size)
+		if tt.reason != "" {
+			checkInputError(t, err, tt.value, tt.reason)
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseHexData(%q, %d) = %q, %v, want %q", tt.value, tt.size, got, err, tt.want)
+		}
+	}
+}